@@ -0,0 +1,92 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/sirupsen/logrus"
+	"github.com/xeipuuv/gojsonschema"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const bodyLocation = "body"
+
+// CreateBodyValidator checks json request bodies against the schema of the body parameter,
+// aborting with 400 and the violations addressed by json pointers. It returns nil when the
+// operation has no body parameter or the mode is ValidationOff.
+func CreateBodyValidator(doc *v2.Document, op *models.Operation, mode ValidationMode) gin.HandlerFunc {
+	bodies := op.GetBodyParameters()
+	if len(bodies) == 0 || bodies[0].Schema == nil || mode == ValidationOff {
+		return nil
+	}
+	param := bodies[0]
+	required := param.Required != nil && *param.Required
+	name := bodyLocation
+	if param.Name != nil {
+		name = *param.Name
+	}
+	schema, err := CompileSchema(doc, param.Schema)
+	if err != nil {
+		logrus.Errorf("body %s of %s is not validated: %s", name, operationName(op), err)
+		return nil
+	}
+	return func(ctx *gin.Context) {
+		if !isJsonMediaType(ctx.ContentType()) && len(ctx.ContentType()) > 0 {
+			return
+		}
+		data, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			AbortWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(data))
+		if len(bytes.TrimSpace(data)) == 0 {
+			if required {
+				reportViolations(ctx, mode, []Violation{{In: bodyLocation, Name: name, Rule: "required", Message: "is required"}})
+			}
+			return
+		}
+		body, err := v2.DecodeJson(data)
+		if err != nil {
+			reportViolations(ctx, mode, []Violation{{In: bodyLocation, Name: name, Rule: "json", Message: err.Error()}})
+			return
+		}
+		if violations := checkSchema(schema, body, bodyLocation); len(violations) > 0 {
+			reportViolations(ctx, mode, violations)
+		}
+	}
+}
+
+// checkSchema validates a decoded json value, naming the violations with json pointers.
+func checkSchema(schema *gojsonschema.Schema, value interface{}, in string) []Violation {
+	result, err := schema.Validate(gojsonschema.NewGoLoader(value))
+	if err != nil {
+		return []Violation{{In: in, Rule: "schema", Message: err.Error()}}
+	}
+	violations := make([]Violation, 0)
+	for _, e := range result.Errors() {
+		violations = append(violations, Violation{In: in, Name: jsonPointer(e), Rule: e.Type(), Message: e.Description()})
+	}
+	return violations
+}
+
+// jsonPointer converts the context of a validation error to a json pointer,
+// pointing at the missing property for required errors.
+func jsonPointer(e gojsonschema.ResultError) string {
+	pointer := strings.TrimPrefix(e.Context().String("/"), gojsonschema.STRING_CONTEXT_ROOT)
+	if property, ok := e.Details()["property"].(string); ok && e.Type() == "required" {
+		pointer += "/" + property
+	}
+	return pointer
+}
+
+func operationName(op *models.Operation) string {
+	if op.OperationId != nil {
+		return *op.OperationId
+	}
+	return fmt.Sprintf("%p", op)
+}
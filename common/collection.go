@@ -0,0 +1,70 @@
+package common
+
+import (
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"strings"
+)
+
+const (
+	collectionCsv   = "csv"
+	collectionSsv   = "ssv"
+	collectionTsv   = "tsv"
+	collectionPipes = "pipes"
+	collectionMulti = "multi"
+)
+
+// joinCollection joins array values following the collection format, "multi" keeping
+// one value per item. Nested arrays are joined with the format of their items.
+func joinCollection(value interface{}, format *string, items *models.PrimitivesItems) []string {
+	arr, ok := value.([]interface{})
+	if !ok {
+		return []string{stringValue(value)}
+	}
+	values := make([]string, 0, len(arr))
+	for _, item := range arr {
+		if items != nil {
+			values = append(values, strings.Join(joinCollection(item, items.CollectionFormat, items.Items), collectionSeparator(items.CollectionFormat)))
+		} else {
+			values = append(values, stringValue(item))
+		}
+	}
+	if isMulti(format) {
+		return values
+	}
+	return []string{strings.Join(values, collectionSeparator(format))}
+}
+
+// splitCollection returns the elements of the raw values of an array, "multi" taking
+// every value as an element and the other formats splitting a single value.
+// It returns false when several values are received for a format other than "multi".
+func splitCollection(values []string, format *string) ([]string, bool) {
+	if isMulti(format) {
+		return values, true
+	}
+	if len(values) > 1 {
+		return nil, false
+	}
+	if len(values) == 0 || len(values[0]) == 0 {
+		return []string{}, true
+	}
+	return strings.Split(values[0], collectionSeparator(format)), true
+}
+
+func collectionSeparator(format *string) string {
+	if format == nil {
+		return ","
+	}
+	switch *format {
+	case collectionSsv:
+		return " "
+	case collectionTsv:
+		return "\t"
+	case collectionPipes:
+		return "|"
+	}
+	return ","
+}
+
+func isMulti(format *string) bool {
+	return format != nil && *format == collectionMulti
+}
@@ -0,0 +1,195 @@
+package common
+
+import (
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+)
+
+const definitionsRef = "#/definitions/"
+
+// mergeAllOf returns the schema with its allOf members, and theirs, merged in.
+// Properties declared by the schema itself win over the ones of its members.
+func (g *Generator) mergeAllOf(schema *models.Schema, seen map[string]bool) *models.Schema {
+	if schema.AllOf == nil {
+		return schema
+	}
+	merged := *schema
+	merged.AllOf = nil
+	if schema.Properties != nil {
+		properties := make(map[string]models.Schema, len(*schema.Properties))
+		for name, prop := range *schema.Properties {
+			properties[name] = prop
+		}
+		merged.Properties = &properties
+	}
+	for _, member := range *schema.AllOf {
+		member := member
+		if member.Ref != nil {
+			if seen[*member.Ref] {
+				continue
+			}
+			seen[*member.Ref] = true
+		}
+		resolved, err := g.resolver.Schema(&member)
+		if err != nil {
+			logrus.Warn(err)
+			continue
+		}
+		mergeSchema(&merged, g.mergeAllOf(resolved, seen))
+	}
+	return &merged
+}
+
+func mergeSchema(into *models.Schema, from *models.Schema) {
+	if into.Type == nil {
+		into.Type = from.Type
+	}
+	if into.Format == nil {
+		into.Format = from.Format
+	}
+	if into.Default == nil {
+		into.Default = from.Default
+	}
+	if into.Discriminator == nil {
+		into.Discriminator = from.Discriminator
+	}
+	if into.Xml == nil {
+		into.Xml = from.Xml
+	}
+	if into.Items == nil {
+		into.Items = from.Items
+	}
+	if into.AdditionalProperties == nil {
+		into.AdditionalProperties = from.AdditionalProperties
+	}
+	if from.Properties != nil {
+		if into.Properties == nil {
+			into.Properties = &map[string]models.Schema{}
+		}
+		for name, prop := range *from.Properties {
+			if _, ok := (*into.Properties)[name]; !ok {
+				(*into.Properties)[name] = prop
+			}
+		}
+	}
+	if from.Required != nil {
+		required := make([]string, 0)
+		if into.Required != nil {
+			required = append(required, *into.Required...)
+		}
+		for _, name := range *from.Required {
+			if !contains(required, name) {
+				required = append(required, name)
+			}
+		}
+		into.Required = &required
+	}
+	mergeRestrictions(&into.Restrictions, &from.Restrictions)
+}
+
+// mergeRestrictions keeps the tightest of both restrictions.
+func mergeRestrictions(into *models.Restrictions, from *models.Restrictions) {
+	if from.Maximum != nil && (into.Maximum == nil || from.Maximum.Cmp(into.Maximum) < 0) {
+		into.Maximum, into.ExclusiveMaximum = from.Maximum, from.ExclusiveMaximum
+	}
+	if from.Minimum != nil && (into.Minimum == nil || from.Minimum.Cmp(into.Minimum) > 0) {
+		into.Minimum, into.ExclusiveMinimum = from.Minimum, from.ExclusiveMinimum
+	}
+	into.MaxLength = minInt(into.MaxLength, from.MaxLength)
+	into.MinLength = maxInt(into.MinLength, from.MinLength)
+	into.MaxItems = minInt(into.MaxItems, from.MaxItems)
+	into.MinItems = maxInt(into.MinItems, from.MinItems)
+	if into.Pattern == nil {
+		into.Pattern = from.Pattern
+	}
+	if into.MultipleOf == nil {
+		into.MultipleOf = from.MultipleOf
+	}
+	if from.UniqueItems != nil && *from.UniqueItems {
+		into.UniqueItems = from.UniqueItems
+	}
+	if from.Enum != nil {
+		if into.Enum == nil {
+			into.Enum = from.Enum
+		} else {
+			enum := make(models.Enum, 0)
+			for _, value := range *into.Enum {
+				if enumContains(*from.Enum, value) {
+					enum = append(enum, value)
+				}
+			}
+			into.Enum = &enum
+		}
+	}
+}
+
+// findSubtypes maps every definition to the definitions referencing it in their allOf.
+func findSubtypes(swagger *models.Swagger) map[string][]string {
+	subtypes := make(map[string][]string)
+	if swagger.Definitions == nil {
+		return subtypes
+	}
+	for name, def := range *swagger.Definitions {
+		if def.AllOf == nil {
+			continue
+		}
+		for _, member := range *def.AllOf {
+			if member.Ref != nil && strings.HasPrefix(*member.Ref, definitionsRef) {
+				base := member.GetRefName()
+				subtypes[base] = append(subtypes[base], name)
+			}
+		}
+	}
+	for base := range subtypes {
+		sort.Strings(subtypes[base])
+	}
+	return subtypes
+}
+
+// subtype picks the concrete definition generated for a discriminator base: the one
+// requested by the caller, otherwise the first subtype, otherwise the base itself.
+func (g *Generator) subtype(base string) string {
+	candidates := g.subtypes[base]
+	for _, preferred := range g.preferred {
+		name := preferred
+		if i := strings.Index(preferred, "="); i >= 0 {
+			if strings.TrimSpace(preferred[:i]) != base {
+				continue
+			}
+			name = preferred[i+1:]
+		}
+		name = strings.TrimSpace(name)
+		if name == base || contains(candidates, name) {
+			return name
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
+	return base
+}
+
+func contains(arr []string, value string) bool {
+	for _, item := range arr {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func minInt(a *int, b *int) *int {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+	return a
+}
+
+func maxInt(a *int, b *int) *int {
+	if a == nil || (b != nil && *b > *a) {
+		return b
+	}
+	return a
+}
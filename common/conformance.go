@@ -0,0 +1,139 @@
+package common
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/sirupsen/logrus"
+	"github.com/xeipuuv/gojsonschema"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ValidationMode tells what happens to a message breaking its definition.
+type ValidationMode string
+
+const (
+	// ValidationOff skips the validation.
+	ValidationOff ValidationMode = "off"
+	// ValidationLenient logs the violations.
+	ValidationLenient ValidationMode = "lenient"
+	// ValidationStrict rejects the message with the violations.
+	ValidationStrict ValidationMode = "strict"
+)
+
+// ParseValidationMode converts a mode name, an empty name being ValidationOff.
+func ParseValidationMode(name string) (ValidationMode, error) {
+	switch mode := ValidationMode(name); mode {
+	case "":
+		return ValidationOff, nil
+	case ValidationOff, ValidationLenient, ValidationStrict:
+		return mode, nil
+	}
+	return ValidationOff, fmt.Errorf("unknown validation mode %q, expected off, lenient or strict", name)
+}
+
+// ResponseChecker validates the responses sent by an operation against the selected
+// response definition, catching generator bugs and examples not matching their schema.
+type ResponseChecker struct {
+	doc  *v2.Document
+	op   *models.Operation
+	mode ValidationMode
+	// schemas holds the compiled schema of every status code, nil when it cannot be compiled.
+	schemas sync.Map
+}
+
+// NewResponseChecker returns nil when the mode is ValidationOff.
+func NewResponseChecker(doc *v2.Document, op *models.Operation, mode ValidationMode) *ResponseChecker {
+	if mode == ValidationOff || len(mode) == 0 {
+		return nil
+	}
+	return &ResponseChecker{doc: doc, op: op, mode: mode}
+}
+
+// CheckHeaders validates the values of the headers declared by the response.
+func (c *ResponseChecker) CheckHeaders(response *models.Response, header http.Header) []Violation {
+	violations := make([]Violation, 0)
+	if response == nil || response.Headers == nil {
+		return violations
+	}
+	names := make([]string, 0, len(*response.Headers))
+	for name := range *response.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h := (*response.Headers)[name]
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		p := primitive{TypeStruct: h.TypeStruct, Restrictions: h.Restrictions, Items: h.Items, CollectionFormat: h.CollectionFormat}
+		for _, v := range checkValues(name, values, p) {
+			v.In = "header"
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// CheckBody validates a response body against the response schema. Json bodies are checked
+// as encoded, other media types through the value they were encoded from; verbatim text
+// examples of other media types are not checked.
+func (c *ResponseChecker) CheckBody(code int, response *models.Response, mediaType string, data []byte, body interface{}) []Violation {
+	if response == nil || response.Schema == nil {
+		return nil
+	}
+	schema := c.schema(code, response.Schema)
+	if schema == nil {
+		return nil
+	}
+	var value interface{}
+	var err error
+	switch _, text := body.(string); {
+	case isJsonMediaType(mediaType):
+		value, err = v2.DecodeJson(data)
+	case text:
+		return nil
+	default:
+		value, err = toGeneric(body)
+	}
+	if err != nil {
+		return []Violation{{In: bodyLocation, Rule: "json", Message: err.Error()}}
+	}
+	return checkSchema(schema, value, bodyLocation)
+}
+
+// Report logs the violations, and in strict mode replaces the response with a 500 listing them.
+// It returns true when the response was replaced.
+func (c *ResponseChecker) Report(ctx *gin.Context, code int, violations []Violation) bool {
+	if len(violations) == 0 {
+		return false
+	}
+	for _, v := range violations {
+		logrus.Warnf("%s %s: %d response %s %s breaks %s: %s", ctx.Request.Method, ctx.Request.URL.Path, code, v.In, v.Name, v.Rule, v.Message)
+	}
+	if c.mode != ValidationStrict {
+		return false
+	}
+	ctx.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{
+		Message:    fmt.Sprintf("the mocked %d response does not match its definition", code),
+		Violations: violations,
+	})
+	return true
+}
+
+func (c *ResponseChecker) schema(code int, schema *models.Schema) *gojsonschema.Schema {
+	if compiled, ok := c.schemas.Load(code); ok {
+		return compiled.(*gojsonschema.Schema)
+	}
+	compiled, err := CompileSchema(c.doc, schema)
+	if err != nil {
+		logrus.Errorf("%d response of %s is not validated: %s", code, operationName(c.op), err)
+		compiled = nil
+	}
+	c.schemas.Store(code, compiled)
+	return compiled
+}
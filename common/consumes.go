@@ -0,0 +1,87 @@
+package common
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/sirupsen/logrus"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Consumes returns the media types of the operation, falling back to the document ones.
+func Consumes(swagger *models.Swagger, op *models.Operation) []string {
+	if op != nil && op.Consumes != nil && len(*op.Consumes) > 0 {
+		return *op.Consumes
+	}
+	if swagger != nil && swagger.Consumes != nil && len(*swagger.Consumes) > 0 {
+		return *swagger.Consumes
+	}
+	return nil
+}
+
+// CreateConsumesValidator rejects the requests with a body whose content type is not consumed
+// by the operation with 415, or only logs them when lenient. It returns nil when the operation
+// does not declare what it consumes.
+func CreateConsumesValidator(swagger *models.Swagger, op *models.Operation, lenient bool) gin.HandlerFunc {
+	consumes := Consumes(swagger, op)
+	if len(consumes) == 0 {
+		return nil
+	}
+	return func(ctx *gin.Context) {
+		contentType := ctx.GetHeader("Content-Type")
+		if len(contentType) == 0 && !hasBody(ctx.Request) {
+			return
+		}
+		if MatchContentType(contentType, consumes) {
+			return
+		}
+		message := fmt.Sprintf("content type %q is not consumed", contentType)
+		if lenient {
+			logrus.Warnf("%s %s: %s, accepted: [%s]", ctx.Request.Method, ctx.Request.URL.Path, message, strings.Join(consumes, ", "))
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusUnsupportedMediaType, ErrorResponse{Message: message, Accepted: consumes})
+	}
+}
+
+// MatchContentType checks a content type against media types, which may use wildcard
+// subtypes. The parameters of a media type, like charset, must be present in the content type.
+func MatchContentType(contentType string, mediaTypes []string) bool {
+	actual, actualParams, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	actualType, actualSub := splitMediaType(actual)
+	for _, mediaType := range mediaTypes {
+		expected, params, err := mime.ParseMediaType(mediaType)
+		if err != nil {
+			continue
+		}
+		expectedType, expectedSub := splitMediaType(expected)
+		if expectedType != "*" && expectedType != actualType {
+			continue
+		}
+		if expectedSub != "*" && expectedSub != actualSub {
+			continue
+		}
+		if matchParams(params, actualParams) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchParams(expected map[string]string, actual map[string]string) bool {
+	for key, value := range expected {
+		if !strings.EqualFold(actual[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasBody(req *http.Request) bool {
+	return req.ContentLength > 0 || len(req.TransferEncoding) > 0
+}
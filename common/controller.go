@@ -1,20 +1,30 @@
 package common
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
 	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
 	"github.com/sirupsen/logrus"
+	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 )
 
+// SubtypeHeader selects the concrete definitions generated for discriminator bases,
+// e.g. "X-Mock-Subtype: Cat" or "X-Mock-Subtype: Pet=Cat, Owner=Person".
+const SubtypeHeader = "X-Mock-Subtype"
+
 type BaseController struct {
 	Path    string
 	Methods []Method
 }
 
 type Method struct {
-	Type    MethodType
-	Handler gin.HandlerFunc
+	Type        MethodType
+	Middlewares []gin.HandlerFunc
+	Handler     gin.HandlerFunc
 }
 
 type MethodType int
@@ -74,30 +84,247 @@ func MethodTypeFromString(m string) MethodType {
 	}
 }
 
-func CreateController(path string, item models.PathItem) BaseController {
+func CreateControllers(doc *v2.Document, opts Options) []BaseController {
+	controllers := make([]BaseController, 0)
+	swagger := doc.Swagger
+	if swagger.Paths == nil {
+		return controllers
+	}
+	paths := make([]string, 0, len(*swagger.Paths))
+	for path := range *swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		controllers = append(controllers, CreateController(doc, path, (*swagger.Paths)[path], opts))
+	}
+	return controllers
+}
+
+func CreateController(doc *v2.Document, path string, item models.PathItem, opts Options) BaseController {
+	resolved, err := doc.Resolver().PathItem(&item)
+	if err != nil {
+		logrus.Errorf("path %s: %s", path, err)
+		resolved = &models.PathItem{}
+	}
 	return BaseController{
 		Path:    ToGinPath(path),
-		Methods: CreateMethods(item),
+		Methods: CreateMethods(doc, *resolved, opts),
 	}
 }
 
-func CreateMethods(item models.PathItem) []Method {
-	globalParams := item.Parameters
+func CreateMethods(doc *v2.Document, item models.PathItem, opts Options) []Method {
+	gParams := ResolveParameters(doc, item.Parameters)
+	operations := []struct {
+		Type      MethodType
+		Operation *models.Operation
+	}{
+		{GET, item.Get},
+		{PUT, item.Put},
+		{POST, item.Post},
+		{DELETE, item.Delete},
+		{PATCH, item.Patch},
+		{OPTIONS, item.Options},
+		{HEAD, item.Head},
+	}
 	methods := make([]Method, 0)
-	if nil != item.Get {
-		get := item.Get
-		m := Method{
-			Type:    GET,
-			Handler: CreateHandler(get, globalParams),
+	for _, o := range operations {
+		if nil != o.Operation {
+			op := MergeParameters(o.Operation, doc, gParams)
+			opOpts := opts.ForOperation(&doc.Swagger, &item, o.Operation)
+			m := Method{
+				Type:        o.Type,
+				Middlewares: make([]gin.HandlerFunc, 0),
+				Handler:     CreateHandler(doc, op, opOpts),
+			}
+			if opOpts.Security {
+				if validator := CreateSecurityValidator(&doc.Swagger, op, opOpts.TokenInspector); validator != nil {
+					m.Middlewares = append(m.Middlewares, validator)
+				}
+			}
+			if validator := CreateConsumesValidator(&doc.Swagger, op, opOpts.LenientConsumes); validator != nil {
+				m.Middlewares = append(m.Middlewares, validator)
+			}
+			if validator := CreateParameterValidator(op, opOpts.RequestValidation); validator != nil {
+				m.Middlewares = append(m.Middlewares, validator)
+			}
+			if validator := CreateBodyValidator(doc, op, opOpts.RequestValidation); validator != nil {
+				m.Middlewares = append(m.Middlewares, validator)
+			}
+			if validator := CreateFormValidator(op, opOpts.RequestValidation); validator != nil {
+				m.Middlewares = append(m.Middlewares, validator)
+			}
+			methods = append(methods, m)
 		}
-		methods = append(methods, m)
 	}
 	return methods
 }
 
-func CreateHandler(op *models.Operation, gParams *[]models.Parameter) gin.HandlerFunc {
+// MergeParameters returns a copy of the operation holding its resolved parameters and
+// the path level parameters not overridden by an operation parameter with the same
+// location and name.
+func MergeParameters(op *models.Operation, doc *v2.Document, gParams *[]models.Parameter) *models.Operation {
+	pathParams := make([]models.Parameter, 0)
+	if gParams != nil {
+		pathParams = *gParams
+	}
+	merged := overrideParameters(pathParams, *ResolveParameters(doc, op.Parameters))
+	result := *op
+	result.Parameters = &merged
+	return &result
+}
+
+// overrideParameters appends the operation parameters to the path ones they do not override.
+func overrideParameters(pathParams []models.Parameter, opParams []models.Parameter) []models.Parameter {
+	overridden := make(map[string]bool)
+	for _, p := range opParams {
+		overridden[p.GetLocationAndName()] = true
+	}
+	merged := make([]models.Parameter, 0, len(pathParams)+len(opParams))
+	for _, p := range pathParams {
+		if !overridden[p.GetLocationAndName()] {
+			merged = append(merged, p)
+		}
+	}
+	return append(merged, opParams...)
+}
+
+func ResolveParameters(doc *v2.Document, params *[]models.Parameter) *[]models.Parameter {
+	resolved := make([]models.Parameter, 0)
+	if params == nil {
+		return &resolved
+	}
+	for _, p := range *params {
+		p := p
+		r, err := doc.Resolver().Parameter(&p)
+		if err != nil {
+			logrus.Error(err)
+			continue
+		}
+		resolved = append(resolved, *r)
+	}
+	return &resolved
+}
+
+func RegisterControllers(engine *gin.Engine, basePath *string, controllers []BaseController) {
+	group := engine.Group("/")
+	if basePath != nil && len(*basePath) > 0 {
+		group = engine.Group(*basePath)
+	}
+	for _, c := range controllers {
+		for _, m := range c.Methods {
+			logrus.Debugf("registering %s %s", m.Type.toString(), c.Path)
+			handlers := append(append([]gin.HandlerFunc{}, m.Middlewares...), m.Handler)
+			group.Handle(strings.ToUpper(m.Type.toString()), c.Path, handlers...)
+		}
+	}
+}
+
+func CreateHandler(doc *v2.Document, op *models.Operation, opts Options) gin.HandlerFunc {
+	generator := NewGenerator(doc).WithData(opts.Data)
+	if opts.ArrayLength > 0 {
+		generator = generator.WithArrayLength(opts.ArrayLength)
+	}
+	checker := NewResponseChecker(doc, op, opts.ResponseValidation)
+	produces := Produces(&doc.Swagger, op)
+	limitParameter := ""
+	if declaresQueryParameter(op, opts.LimitParameter) {
+		limitParameter = opts.LimitParameter
+	}
 	return func(ctx *gin.Context) {
+		code, response, err := SelectResponse(doc, op, RequestedStatus(ctx.Request))
+		if err != nil {
+			AbortWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		seed := RequestSeed(ctx, op, opts.Seed)
+		ctx.Header(SeedHeader, strconv.FormatInt(seed, 10))
+		g := generator.WithSubtypes(splitHeader(ctx.GetHeader(SubtypeHeader))).WithSeed(seed)
+		if form, ok := ctx.Get(FormDataKey); ok {
+			g = g.WithValues(form.(*FormData).Values())
+		}
+		if limit, ok := requestLimit(ctx, limitParameter); ok {
+			g = g.WithLimit(limit)
+		}
+		// the mocked headers are only written with the mocked response, not with the errors
+		var headers http.Header
+		if response != nil {
+			headers = g.GenerateHeaders(response)
+			if checker != nil && checker.Report(ctx, code, checker.CheckHeaders(response, headers)) {
+				return
+			}
+		}
+		if response == nil || (response.Schema == nil && response.Examples == nil) {
+			writeHeaders(ctx, headers)
+			ctx.Status(code)
+			return
+		}
+		mediaType, ok := Negotiate(ctx.GetHeader("Accept"), produces)
+		if !ok {
+			AbortWithError(ctx, http.StatusNotAcceptable, fmt.Sprintf("none of the accepted media types is produced, available: [%s]", strings.Join(produces, ", ")))
+			return
+		}
+		if ctx.Request.Method == http.MethodHead {
+			writeHeaders(ctx, headers)
+			ctx.Header("Content-Type", mediaType)
+			ctx.Status(code)
+			return
+		}
+		body, ok := Example(response, mediaType)
+		if !ok {
+			if response.Schema == nil {
+				writeHeaders(ctx, headers)
+				ctx.Status(code)
+				return
+			}
+			body = g.Generate(response.Schema)
+		}
+		data, err := g.Encode(mediaType, response.Schema, body)
+		if err != nil {
+			AbortWithError(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if checker != nil && checker.Report(ctx, code, checker.CheckBody(code, response, mediaType, data, body)) {
+			return
+		}
+		writeHeaders(ctx, headers)
+		ctx.Data(code, mediaType, data)
+	}
+}
+
+func writeHeaders(ctx *gin.Context, headers http.Header) {
+	for name, values := range headers {
+		for _, value := range values {
+			ctx.Writer.Header().Add(name, value)
+		}
+	}
+}
+
+// declaresQueryParameter tells whether the operation has a query parameter with the name.
+func declaresQueryParameter(op *models.Operation, name string) bool {
+	if len(name) == 0 || op.Parameters == nil {
+		return false
+	}
+	for _, p := range *op.Parameters {
+		if p.In != nil && *p.In == "query" && p.Name != nil && *p.Name == name {
+			return true
+		}
+	}
+	return false
+}
 
-		//TODO implement
+// requestLimit reads the number of items requested by the limit query parameter.
+func requestLimit(ctx *gin.Context, name string) (int, bool) {
+	if len(name) == 0 {
+		return 0, false
+	}
+	raw, ok := ctx.GetQuery(name)
+	if !ok {
+		return 0, false
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 0 {
+		return 0, false
 	}
+	return limit, true
 }
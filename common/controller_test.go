@@ -0,0 +1,111 @@
+package common
+
+import (
+	"github.com/gin-gonic/gin"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const headersSpec = `swagger: "2.0"
+info: {title: headers, version: "1"}
+produces: [application/json]
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          schema: {type: array, items: {type: string}}
+          headers:
+            X-Rate-Limit: {type: integer, minimum: 10}
+            X-Ids: {type: array, items: {type: integer}}
+        "201":
+          description: no content
+          headers:
+            X-Rate-Limit: {type: integer, minimum: 10}
+`
+
+func TestCreateHandlerHeaders(t *testing.T) {
+	doc, err := v2.LoadBytes([]byte(headersSpec), "spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/pets", CreateHandler(doc, (*doc.Swagger.Paths)["/pets"].Get, Options{}))
+	tests := []struct {
+		name        string
+		accept      string
+		status      string
+		want        int
+		wantHeaders bool
+	}{
+		{name: "mocked response", accept: "application/json", want: http.StatusOK, wantHeaders: true},
+		{name: "response without body", status: "201", want: http.StatusCreated, wantHeaders: true},
+		{name: "not acceptable", accept: "image/png", want: http.StatusNotAcceptable},
+		{name: "unknown status", status: "418", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+			req.Header.Set("Accept", tt.accept)
+			if len(tt.status) > 0 {
+				req.Header.Set(StatusHeader, tt.status)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if got := len(w.Header().Get("X-Rate-Limit")) > 0; got != tt.wantHeaders {
+				t.Errorf("X-Rate-Limit set = %v, want %v", got, tt.wantHeaders)
+			}
+			if got := len(w.Header().Get("X-Ids")) > 0; got && !tt.wantHeaders {
+				t.Errorf("X-Ids set on a %d error", w.Code)
+			}
+		})
+	}
+}
+
+const limitSpec = `swagger: "2.0"
+info: {title: limit, version: "1"}
+paths:
+  /pets:
+    get:
+      parameters: [{name: limit, in: query, type: integer}]
+      responses: {"200": {description: ok, schema: {type: array, items: {type: integer}}}}
+  /owners:
+    get:
+      responses: {"200": {description: ok, schema: {type: array, items: {type: integer}}}}
+`
+
+func TestCreateHandlerLimit(t *testing.T) {
+	doc, err := v2.LoadBytes([]byte(limitSpec), "spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	opts := Options{LimitParameter: "limit"}
+	engine.GET("/pets", CreateHandler(doc, (*doc.Swagger.Paths)["/pets"].Get, opts))
+	engine.GET("/owners", CreateHandler(doc, (*doc.Swagger.Paths)["/owners"].Get, opts))
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "/pets", want: "[0]"},
+		{url: "/pets?limit=3", want: "[0,0,0]"},
+		{url: "/owners?limit=3", want: "[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("GET %s = %s, want %s", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package common
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ErrorResponse is the body of the errors raised by the mock itself,
+// as opposed to the error responses declared by the spec.
+type ErrorResponse struct {
+	Message    string      `json:"message"`
+	Violations []Violation `json:"violations,omitempty"`
+	// Accepted lists the media types the operation consumes.
+	Accepted []string `json:"accepted,omitempty"`
+}
+
+func AbortWithError(ctx *gin.Context, code int, message string) {
+	ctx.AbortWithStatusJSON(code, ErrorResponse{Message: message})
+}
+
+func AbortWithViolations(ctx *gin.Context, violations []Violation) {
+	ctx.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid request", Violations: violations})
+}
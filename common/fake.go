@@ -0,0 +1,345 @@
+package common
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"math/big"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// DataMode selects how the generated values are filled.
+type DataMode string
+
+const (
+	// DataDefaults fills the zero value of every type.
+	DataDefaults DataMode = "defaults"
+	// DataRealistic fills plausible values, guessed from the format and the property name.
+	DataRealistic DataMode = "realistic"
+)
+
+// ParseDataMode converts a data mode name, an empty name being DataDefaults.
+func ParseDataMode(name string) (DataMode, error) {
+	switch mode := DataMode(name); mode {
+	case "":
+		return DataDefaults, nil
+	case DataDefaults, DataRealistic:
+		return mode, nil
+	}
+	return DataDefaults, fmt.Errorf("unknown data mode %q, expected defaults or realistic", name)
+}
+
+var (
+	firstNames = []string{"Olivia", "Liam", "Emma", "Noah", "Ava", "Elijah", "Sophia", "Lucas", "Mia", "Mateo", "Amelia", "Levi"}
+	lastNames  = []string{"Smith", "Johnson", "Garcia", "Miller", "Davis", "Martinez", "Lopez", "Wilson", "Anderson", "Taylor", "Moore", "Clark"}
+	cities     = []string{"Lisbon", "Toronto", "Osaka", "Berlin", "Austin", "Melbourne", "Bucharest", "Nairobi", "Oslo", "Denver"}
+	countries  = []string{"Portugal", "Canada", "Japan", "Germany", "United States", "Australia", "Romania", "Kenya", "Norway"}
+	states     = []string{"California", "Texas", "Ontario", "Bavaria", "Victoria", "Oregon", "Quebec", "Colorado"}
+	streets    = []string{"Maple Street", "Oak Avenue", "Cedar Lane", "Park Road", "Hillside Drive", "River Walk", "Station Square"}
+	companies  = []string{"Acme Corp", "Globex", "Initech", "Umbrella Labs", "Stark Industries", "Hooli", "Vandelay Imports"}
+	domains    = []string{"example.com", "example.org", "example.net"}
+	colors     = []string{"red", "green", "blue", "orange", "purple", "teal", "black", "white"}
+	currencies = []string{"USD", "EUR", "GBP", "JPY", "CAD", "RON"}
+	locales    = []string{"en-US", "en-GB", "fr-FR", "de-DE", "ja-JP", "ro-RO"}
+	words      = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do", "eiusmod", "tempor", "incididunt", "labore", "magna", "aliqua"}
+)
+
+// fakeDate is the start of the dates generated in realistic mode, which span the 5 years after it.
+var fakeDate = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fakeString generates a plausible string, guessed from the format and then the property name.
+func fakeString(rnd *rand.Rand, name string, format string) string {
+	switch format {
+	case "date":
+		return fakeTime(rnd).Format("2006-01-02")
+	case "date-time":
+		return fakeTime(rnd).Format(time.RFC3339)
+	case "email":
+		return fakeEmail(rnd)
+	case "uuid":
+		return fakeUuid(rnd)
+	case "uri", "url":
+		return fakeUrl(rnd)
+	case "hostname":
+		return fmt.Sprintf("%s.%s", pick(rnd, words), pick(rnd, domains))
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", 1+rnd.Intn(254))
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", 1+rnd.Intn(0xfffe))
+	case "byte":
+		return base64.StdEncoding.EncodeToString([]byte(fakeSentence(rnd, 3)))
+	case "binary":
+		return fakeSentence(rnd, 3)
+	case "password":
+		return fakePassword(rnd)
+	}
+	key := normalizeName(name)
+	switch {
+	case strings.Contains(key, "email"):
+		return fakeEmail(rnd)
+	case hasAny(key, "firstname", "givenname", "forename"):
+		return pick(rnd, firstNames)
+	case hasAny(key, "lastname", "surname", "familyname"):
+		return pick(rnd, lastNames)
+	case hasAny(key, "username", "login", "nickname", "handle"):
+		return strings.ToLower(pick(rnd, firstNames)) + fmt.Sprint(rnd.Intn(100))
+	case hasAny(key, "fullname", "displayname", "owner", "author", "contact"):
+		return pick(rnd, firstNames) + " " + pick(rnd, lastNames)
+	case hasAny(key, "password", "secret"):
+		return fakePassword(rnd)
+	case hasAny(key, "phone", "mobile", "telephone", "fax"):
+		return fmt.Sprintf("+1-555-%03d-%04d", rnd.Intn(1000), rnd.Intn(10000))
+	case hasAny(key, "photo", "image", "avatar", "picture", "thumbnail", "logo"):
+		return fmt.Sprintf("https://%s/images/%s.jpg", pick(rnd, domains), pick(rnd, words))
+	case hasAny(key, "url", "uri", "link", "website", "homepage", "href"):
+		return fakeUrl(rnd)
+	case hasAny(key, "hostname", "domain"):
+		return fmt.Sprintf("%s.%s", pick(rnd, words), pick(rnd, domains))
+	case hasAny(key, "ipaddress", "ipv4") || key == "ip":
+		return fmt.Sprintf("192.0.2.%d", 1+rnd.Intn(254))
+	case hasAny(key, "street", "address"):
+		return fmt.Sprintf("%d %s", 1+rnd.Intn(999), pick(rnd, streets))
+	case strings.Contains(key, "city"):
+		return pick(rnd, cities)
+	case strings.Contains(key, "country"):
+		return pick(rnd, countries)
+	case hasAny(key, "state", "province", "region"):
+		return pick(rnd, states)
+	case hasAny(key, "zip", "postal", "postcode"):
+		return fmt.Sprintf("%05d", rnd.Intn(100000))
+	case hasAny(key, "company", "organization", "organisation", "employer"):
+		return pick(rnd, companies)
+	case hasAny(key, "colour", "color"):
+		return pick(rnd, colors)
+	case strings.Contains(key, "currency"):
+		return pick(rnd, currencies)
+	case hasAny(key, "locale", "language"):
+		return pick(rnd, locales)
+	case hasAny(key, "uuid", "guid") || isIdName(name):
+		return fakeUuid(rnd)
+	case hasAny(key, "createdat", "updatedat", "modifiedat", "timestamp", "datetime"):
+		return fakeTime(rnd).Format(time.RFC3339)
+	case hasAny(key, "date", "birthday", "dob"):
+		return fakeTime(rnd).Format("2006-01-02")
+	case hasAny(key, "description", "summary", "comment", "message", "bio", "text", "note"):
+		return capitalize(fakeSentence(rnd, 8)) + "."
+	case strings.Contains(key, "title"):
+		return capitalize(fakeSentence(rnd, 3))
+	case strings.Contains(key, "name"):
+		return pick(rnd, firstNames)
+	}
+	return pick(rnd, words)
+}
+
+// fakeNumber generates a plausible number, guessed from the property name. Integers are
+// generated for the integer type.
+func fakeNumber(rnd *rand.Rand, name string, integer bool) float64 {
+	key := normalizeName(name)
+	var value float64
+	switch {
+	case key == "age" || strings.HasSuffix(name, "Age"):
+		value = float64(18 + rnd.Intn(63))
+	case strings.Contains(key, "year"):
+		value = float64(fakeTime(rnd).Year())
+	case hasAny(key, "latitude") || key == "lat":
+		value = rnd.Float64()*180 - 90
+	case hasAny(key, "longitude") || key == "lng" || key == "lon":
+		value = rnd.Float64()*360 - 180
+	case hasAny(key, "price", "amount", "cost", "total", "balance", "salary", "fee"):
+		value = float64(rnd.Intn(50000)) / 100
+	case hasAny(key, "quantity", "count", "size", "number", "qty"):
+		value = float64(1 + rnd.Intn(20))
+	case hasAny(key, "percent", "ratio", "rating", "score"):
+		value = float64(rnd.Intn(1000)) / 10
+	default:
+		value = float64(1 + rnd.Intn(1000))
+	}
+	if integer {
+		return float64(int64(value))
+	}
+	return value
+}
+
+// fitNumber moves a number within the bounds of the restrictions, on a multiple of multipleOf.
+// The steps are computed exactly, so decimal multiples like 0.01 do not drift. Without
+// multipleOf, numbers use a 0.01 step unless the range is narrower, taking its middle.
+func fitNumber(value float64, r models.Restrictions, integer bool) *big.Rat {
+	v := new(big.Rat).SetFloat64(value)
+	step := big.NewRat(1, 1)
+	if !integer {
+		step = big.NewRat(1, 100)
+	}
+	multipleOf := r.MultipleOf != nil && r.MultipleOf.Rat().Sign() > 0
+	if multipleOf {
+		step = r.MultipleOf.Rat()
+		if integer {
+			// the integer multiples of p/q are the multiples of p
+			step = new(big.Rat).SetInt(step.Num())
+		}
+		v = roundStep(v, step)
+	}
+	if r.Minimum != nil {
+		min := r.Minimum.Rat()
+		exclusive := r.ExclusiveMinimum != nil && *r.ExclusiveMinimum
+		if c := v.Cmp(min); c < 0 || (c == 0 && exclusive) {
+			v = ceilStep(min, step)
+			if v.Cmp(min) == 0 && exclusive {
+				v.Add(v, step)
+			}
+		}
+	}
+	if r.Maximum != nil {
+		max := r.Maximum.Rat()
+		exclusive := r.ExclusiveMaximum != nil && *r.ExclusiveMaximum
+		if c := v.Cmp(max); c > 0 || (c == 0 && exclusive) {
+			v = floorStep(max, step)
+			if v.Cmp(max) == 0 && exclusive {
+				v.Sub(v, step)
+			}
+		}
+	}
+	if !integer && !multipleOf && r.Minimum != nil && r.Maximum != nil && !withinBounds(v, r) {
+		// no multiple of the step fits, any number between the bounds does
+		v = new(big.Rat).Add(r.Minimum.Rat(), r.Maximum.Rat())
+		v.Quo(v, big.NewRat(2, 1))
+	}
+	return v
+}
+
+// withinBounds tells whether a number satisfies the minimum and maximum restrictions.
+func withinBounds(v *big.Rat, r models.Restrictions) bool {
+	if r.Minimum != nil {
+		c := v.Cmp(r.Minimum.Rat())
+		if c < 0 || (c == 0 && r.ExclusiveMinimum != nil && *r.ExclusiveMinimum) {
+			return false
+		}
+	}
+	if r.Maximum != nil {
+		c := v.Cmp(r.Maximum.Rat())
+		if c > 0 || (c == 0 && r.ExclusiveMaximum != nil && *r.ExclusiveMaximum) {
+			return false
+		}
+	}
+	return true
+}
+
+// decimalString formats a number with the digits needed to write it exactly, numbers built
+// from decimal restrictions having a finite decimal expansion. Others are rounded to 20 digits.
+func decimalString(v *big.Rat) string {
+	for precision := 0; precision < 20; precision++ {
+		s := v.FloatString(precision)
+		if parsed, ok := new(big.Rat).SetString(s); ok && parsed.Cmp(v) == 0 {
+			return s
+		}
+	}
+	return v.FloatString(20)
+}
+
+// fitString pads or truncates a string to the length restrictions.
+func fitString(value string, r models.Restrictions) string {
+	runes := []rune(value)
+	if r.MaxLength != nil && len(runes) > *r.MaxLength {
+		runes = runes[:*r.MaxLength]
+	}
+	for r.MinLength != nil && len(runes) < *r.MinLength {
+		runes = append(runes, 'x')
+	}
+	return string(runes)
+}
+
+func ceilStep(value *big.Rat, step *big.Rat) *big.Rat {
+	steps := floorQuo(value, step)
+	if new(big.Rat).Mul(new(big.Rat).SetInt(steps), step).Cmp(value) < 0 {
+		steps.Add(steps, big.NewInt(1))
+	}
+	return new(big.Rat).Mul(new(big.Rat).SetInt(steps), step)
+}
+
+// roundStep returns the multiple of step nearest to value, 0.3 being 0.3 even when given
+// as the float64 0.29999999999999998.
+func roundStep(value *big.Rat, step *big.Rat) *big.Rat {
+	half := new(big.Rat).Quo(step, big.NewRat(2, 1))
+	return floorStep(new(big.Rat).Add(value, half), step)
+}
+
+func floorStep(value *big.Rat, step *big.Rat) *big.Rat {
+	return new(big.Rat).Mul(new(big.Rat).SetInt(floorQuo(value, step)), step)
+}
+
+// floorQuo returns the largest integer <= value / step.
+func floorQuo(value *big.Rat, step *big.Rat) *big.Int {
+	q := new(big.Rat).Quo(value, step)
+	// the denominator is positive, so the euclidean division rounds down
+	return new(big.Int).Div(q.Num(), q.Denom())
+}
+
+func fakeTime(rnd *rand.Rand) time.Time {
+	return fakeDate.Add(time.Duration(rnd.Int63n(int64(5 * 365 * 24 * time.Hour)))).Truncate(time.Second)
+}
+
+func fakeEmail(rnd *rand.Rand) string {
+	return fmt.Sprintf("%s.%s@%s", strings.ToLower(pick(rnd, firstNames)), strings.ToLower(pick(rnd, lastNames)), pick(rnd, domains))
+}
+
+func fakeUuid(rnd *rand.Rand) string {
+	b := make([]byte, 16)
+	rnd.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func fakeUrl(rnd *rand.Rand) string {
+	return fmt.Sprintf("https://%s/%s", pick(rnd, domains), pick(rnd, words))
+}
+
+func fakePassword(rnd *rand.Rand) string {
+	const alphabet = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789!#$%"
+	b := make([]byte, 12)
+	for i := range b {
+		b[i] = alphabet[rnd.Intn(len(alphabet))]
+	}
+	return string(b)
+}
+
+func fakeSentence(rnd *rand.Rand, count int) string {
+	sentence := make([]string, count)
+	for i := range sentence {
+		sentence[i] = pick(rnd, words)
+	}
+	return strings.Join(sentence, " ")
+}
+
+func capitalize(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func pick(rnd *rand.Rand, values []string) string {
+	return values[rnd.Intn(len(values))]
+}
+
+// normalizeName lower cases a property name and drops its separators, "first_name" and
+// "firstName" both becoming "firstname".
+func normalizeName(name string) string {
+	return strings.NewReplacer("_", "", "-", "", " ", "", ".", "").Replace(strings.ToLower(name))
+}
+
+// isIdName matches "id", "userId", "userID" and "user_id".
+func isIdName(name string) bool {
+	return strings.EqualFold(name, "id") || strings.HasSuffix(name, "Id") || strings.HasSuffix(name, "ID") ||
+		strings.HasSuffix(strings.ToLower(name), "_id")
+}
+
+func hasAny(key string, fragments ...string) bool {
+	for _, f := range fragments {
+		if strings.Contains(key, f) {
+			return true
+		}
+	}
+	return false
+}
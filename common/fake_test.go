@@ -0,0 +1,65 @@
+package common
+
+import (
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"math/big"
+	"testing"
+)
+
+func number(t *testing.T, text string) *models.Number {
+	t.Helper()
+	n, err := models.NewNumber(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestFitNumber(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        float64
+		integer      bool
+		min, max     string
+		exclusiveMin bool
+		exclusiveMax bool
+		multipleOf   string
+		want         string
+	}{
+		{name: "unrestricted", value: 12.5, want: "25/2"},
+		{name: "below minimum", value: 0, min: "12.3456", want: "1235/100"},
+		{name: "above maximum", value: 150, max: "99.5", want: "199/2"},
+		{name: "exclusive minimum", value: 0, min: "3", exclusiveMin: true, want: "301/100"},
+		{name: "exclusive maximum", value: 150, max: "99.5", exclusiveMax: true, want: "9949/100"},
+		{name: "exclusive integer bounds", value: 0, integer: true, min: "3", exclusiveMin: true, max: "5", exclusiveMax: true, want: "4"},
+		{name: "decimal multiple", value: 0.3, multipleOf: "0.1", want: "3/10"},
+		{name: "decimal multiple above maximum", value: 150, max: "99.999", multipleOf: "0.01", want: "9999/100"},
+		{name: "exclusive bound on a multiple", value: 0, min: "0.35", exclusiveMin: true, multipleOf: "0.05", want: "2/5"},
+		{name: "integer fractional multiple", value: 0, integer: true, min: "3", multipleOf: "1.5", want: "3"},
+		{name: "range narrower than the step", value: 0, min: "0.001", max: "0.002", want: "3/2000"},
+		{name: "exclusive range narrower than the step", value: 0, min: "0.001", max: "0.002", exclusiveMin: true, exclusiveMax: true, want: "3/2000"},
+		{name: "range between two steps", value: 5, min: "0.011", max: "0.019", want: "3/200"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := models.Restrictions{ExclusiveMinimum: &tt.exclusiveMin, ExclusiveMaximum: &tt.exclusiveMax}
+			if len(tt.min) > 0 {
+				r.Minimum = number(t, tt.min)
+			}
+			if len(tt.max) > 0 {
+				r.Maximum = number(t, tt.max)
+			}
+			if len(tt.multipleOf) > 0 {
+				r.MultipleOf = number(t, tt.multipleOf)
+			}
+			got := fitNumber(tt.value, r, tt.integer)
+			want, _ := new(big.Rat).SetString(tt.want)
+			if got.Cmp(want) != 0 {
+				t.Errorf("fitNumber() = %s, want %s", got.RatString(), want.RatString())
+			}
+			if !withinBounds(got, r) {
+				t.Errorf("fitNumber() = %s is out of bounds", got.RatString())
+			}
+		})
+	}
+}
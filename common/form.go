@@ -0,0 +1,174 @@
+package common
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/sirupsen/logrus"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FormDataKey is the gin context key of the parsed *FormData.
+	FormDataKey      = "mock.formData"
+	formLocation     = "formData"
+	multipartMemory  = 32 << 20
+	multipartContent = "multipart/form-data"
+	// MaxSizeExtension and MinSizeExtension bound the size in bytes of the files of a file parameter.
+	MaxSizeExtension = "x-max-size"
+	MinSizeExtension = "x-min-size"
+)
+
+// FormData holds the form fields and the metadata of the files received by an operation.
+type FormData struct {
+	Fields map[string][]string
+	Files  map[string][]FileInfo
+}
+
+type FileInfo struct {
+	Filename    string `json:"filename"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"contentType"`
+}
+
+// Values returns the first value of every field, and the name of the first file of every
+// file parameter. The metadata of the first file also fills the "filename", "size" and
+// "contentType" (or "mimeType") properties, keyed by their normalized name.
+func (f *FormData) Values() map[string]string {
+	values := make(map[string]string)
+	for _, name := range sortedKeys(f.Files) {
+		files := f.Files[name]
+		if len(files) == 0 {
+			continue
+		}
+		values[name] = files[0].Filename
+		metadata := map[string]string{
+			"filename":    files[0].Filename,
+			"size":        strconv.FormatInt(files[0].Size, 10),
+			"contenttype": files[0].ContentType,
+			"mimetype":    files[0].ContentType,
+		}
+		for key, value := range metadata {
+			if _, ok := values[key]; !ok && len(value) > 0 {
+				values[key] = value
+			}
+		}
+	}
+	// fields win over the file metadata
+	for name, v := range f.Fields {
+		if len(v) > 0 {
+			values[name] = v[0]
+		}
+	}
+	return values
+}
+
+// CreateFormValidator parses url encoded and multipart bodies, checks every formData parameter
+// and stores the parsed *FormData in the context. File parameters are bounded by the x-max-size
+// and x-min-size extensions, in bytes. In ValidationOff mode the form is only parsed. It returns
+// nil when the operation has no formData parameter.
+func CreateFormValidator(op *models.Operation, mode ValidationMode) gin.HandlerFunc {
+	params := op.GetFormDataParameters()
+	if len(params) == 0 {
+		return nil
+	}
+	for _, p := range params {
+		if p.Type == nil || *p.Type != "file" {
+			continue
+		}
+		for _, name := range []string{MaxSizeExtension, MinSizeExtension} {
+			if _, ok := p.Extensions.Get(name); ok {
+				if _, ok := p.Extensions.GetInt(name); !ok {
+					logrus.Warnf("%s: %s of file parameter %s is not an integer, ignoring it", operationName(op), name, *p.Name)
+				}
+			}
+		}
+	}
+	return func(ctx *gin.Context) {
+		form, err := parseForm(ctx.Request)
+		if err != nil {
+			if mode != ValidationOff {
+				reportViolations(ctx, mode, []Violation{{In: formLocation, Rule: "form", Message: err.Error()}})
+			}
+			return
+		}
+		if mode != ValidationOff {
+			if violations := checkForm(params, form); len(violations) > 0 {
+				reportViolations(ctx, mode, violations)
+				if ctx.IsAborted() {
+					return
+				}
+			}
+		}
+		ctx.Set(FormDataKey, form)
+	}
+}
+
+func checkForm(params []models.Parameter, form *FormData) []Violation {
+	violations := make([]Violation, 0)
+	for _, p := range params {
+		p := p
+		if p.Type != nil && *p.Type == "file" {
+			violations = append(violations, checkFiles(&p, form.Files[*p.Name])...)
+			continue
+		}
+		values, found := form.Fields[*p.Name]
+		violations = append(violations, checkParameter(&p, values, found)...)
+	}
+	return violations
+}
+
+func parseForm(req *http.Request) (*FormData, error) {
+	form := &FormData{Fields: make(map[string][]string), Files: make(map[string][]FileInfo)}
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("Content-Type")), multipartContent) {
+		if err := req.ParseMultipartForm(multipartMemory); err != nil {
+			return nil, err
+		}
+		for name, values := range req.MultipartForm.Value {
+			form.Fields[name] = values
+		}
+		for name, headers := range req.MultipartForm.File {
+			form.Files[name] = fileInfos(headers)
+		}
+		return form, nil
+	}
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+	for name, values := range req.PostForm {
+		form.Fields[name] = values
+	}
+	return form, nil
+}
+
+func fileInfos(headers []*multipart.FileHeader) []FileInfo {
+	files := make([]FileInfo, 0, len(headers))
+	for _, h := range headers {
+		files = append(files, FileInfo{Filename: h.Filename, Size: h.Size, ContentType: h.Header.Get("Content-Type")})
+	}
+	return files
+}
+
+func checkFiles(p *models.Parameter, files []FileInfo) []Violation {
+	if len(files) == 0 {
+		if p.Required != nil && *p.Required {
+			return []Violation{{In: formLocation, Name: *p.Name, Rule: "required", Message: "is required"}}
+		}
+		return nil
+	}
+	violations := make([]Violation, 0)
+	maxSize, hasMax := p.Extensions.GetInt(MaxSizeExtension)
+	minSize, hasMin := p.Extensions.GetInt(MinSizeExtension)
+	for _, f := range files {
+		if hasMax && f.Size > maxSize {
+			violations = append(violations, Violation{In: formLocation, Name: *p.Name, Rule: MaxSizeExtension, Message: fmt.Sprintf("%s is %d bytes, must be <= %d", f.Filename, f.Size, maxSize)})
+		}
+		if hasMin && f.Size < minSize {
+			violations = append(violations, Violation{In: formLocation, Name: *p.Name, Rule: MinSizeExtension, Message: fmt.Sprintf("%s is %d bytes, must be >= %d", f.Filename, f.Size, minSize)})
+		}
+	}
+	return violations
+}
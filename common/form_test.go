@@ -0,0 +1,84 @@
+package common
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const uploadSpec = `swagger: "2.0"
+info: {title: upload, version: "1"}
+paths:
+  /files:
+    post:
+      consumes: [multipart/form-data]
+      parameters:
+        - {name: file, in: formData, type: file, required: true, x-min-size: 2, x-max-size: 8}
+        - {name: note, in: formData, type: string, maxLength: 5}
+      responses: {"200": {description: ok}}
+`
+
+func TestCreateFormValidator(t *testing.T) {
+	doc, err := v2.LoadBytes([]byte(uploadSpec), "spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	var received *FormData
+	engine.POST("/files", CreateFormValidator((*doc.Swagger.Paths)["/files"].Post, ValidationStrict), func(ctx *gin.Context) {
+		form, _ := ctx.Get(FormDataKey)
+		received = form.(*FormData)
+		ctx.Status(http.StatusOK)
+	})
+	tests := []struct {
+		name     string
+		content  string
+		note     string
+		want     int
+		wantRule string
+	}{
+		{name: "within the size bounds", content: "hello", note: "hi", want: http.StatusOK},
+		{name: "too large", content: "hello world", want: http.StatusBadRequest, wantRule: MaxSizeExtension},
+		{name: "too small", content: "h", want: http.StatusBadRequest, wantRule: MinSizeExtension},
+		{name: "missing file", want: http.StatusBadRequest, wantRule: "required"},
+		{name: "string field keeps maxLength", content: "hello", note: "too long", want: http.StatusBadRequest, wantRule: "maxLength"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			if len(tt.content) > 0 {
+				part, _ := writer.CreateFormFile("file", "a.txt")
+				part.Write([]byte(tt.content))
+			}
+			if len(tt.note) > 0 {
+				writer.WriteField("note", tt.note)
+			}
+			writer.Close()
+			req := httptest.NewRequest(http.MethodPost, "/files", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if len(tt.wantRule) > 0 && !strings.Contains(w.Body.String(), `"rule":"`+tt.wantRule+`"`) {
+				t.Errorf("body = %s, want a %s violation", w.Body.String(), tt.wantRule)
+			}
+		})
+	}
+	want := map[string]string{"file": "a.txt", "filename": "a.txt", "size": "5", "contenttype": "application/octet-stream", "mimetype": "application/octet-stream", "note": "hi"}
+	if received == nil {
+		t.Fatal("no form received")
+	}
+	if got := received.Values(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Values() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,435 @@
+package common
+
+import (
+	"encoding/json"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultArrayLength is the number of items of the generated arrays, within minItems and maxItems.
+	defaultArrayLength = 1
+	// maxArrayLength bounds the requested array lengths.
+	maxArrayLength = 1000
+	// uniqueAttempts bounds the values generated while looking for an item not in the array yet.
+	uniqueAttempts = 20
+)
+
+// Generator walks a models.Schema and builds a payload filled with default or realistic values.
+type Generator struct {
+	resolver *v2.Resolver
+	// subtypes maps a discriminator base definition to the definitions extending it.
+	subtypes map[string][]string
+	// preferred holds the subtypes requested by the caller.
+	preferred []string
+	// values holds request values filling the root object properties with the same name.
+	values map[string]string
+	data   DataMode
+	rnd    *rand.Rand
+	// length is the number of items of the generated arrays.
+	length int
+	// limit, when set, replaces length for the listing: the root array, or the only array
+	// property of the root object.
+	limit *int
+}
+
+func NewGenerator(doc *v2.Document) *Generator {
+	return &Generator{
+		resolver: doc.Resolver(),
+		subtypes: findSubtypes(&doc.Swagger),
+		length:   defaultArrayLength,
+	}
+}
+
+// WithSubtypes returns a copy of the generator preferring the given definitions,
+// as "Subtype" or "Base=Subtype", when a discriminator base is generated.
+func (g *Generator) WithSubtypes(preferred []string) *Generator {
+	c := *g
+	c.preferred = preferred
+	return &c
+}
+
+// WithValues returns a copy of the generator filling the properties of the root object named
+// after the values, or whose normalized name is the key, with those values when they are
+// valid for the property type.
+func (g *Generator) WithValues(values map[string]string) *Generator {
+	c := *g
+	c.values = values
+	return &c
+}
+
+// WithData returns a copy of the generator filling the values of the data mode.
+func (g *Generator) WithData(data DataMode) *Generator {
+	c := *g
+	c.data = data
+	return &c
+}
+
+// WithSeed returns a copy of the generator drawing the realistic values from a source
+// seeded with the seed. The copy must not be shared between goroutines.
+func (g *Generator) WithSeed(seed int64) *Generator {
+	c := *g
+	c.rnd = rand.New(rand.NewSource(seed))
+	return &c
+}
+
+// WithArrayLength returns a copy of the generator filling the arrays with length items,
+// within their minItems and maxItems.
+func (g *Generator) WithArrayLength(length int) *Generator {
+	c := *g
+	c.length = length
+	return &c
+}
+
+// WithLimit returns a copy of the generator filling the listing, the root array or the only
+// array property of the root object, with limit items, like a page.
+func (g *Generator) WithLimit(limit int) *Generator {
+	c := *g
+	c.limit = &limit
+	return &c
+}
+
+func (g *Generator) withoutLimit() *Generator {
+	if g.limit == nil {
+		return g
+	}
+	c := *g
+	c.limit = nil
+	return &c
+}
+
+func (g *Generator) withoutValues() *Generator {
+	if g.values == nil {
+		return g
+	}
+	c := *g
+	c.values = nil
+	return &c
+}
+
+// value returns the request value of a property, matched by name or normalized name.
+func (g *Generator) value(name string) (string, bool) {
+	if raw, ok := g.values[name]; ok {
+		return raw, true
+	}
+	raw, ok := g.values[normalizeName(name)]
+	return raw, ok
+}
+
+func (g *Generator) Generate(schema *models.Schema) interface{} {
+	return g.generate(schema, "", make(map[string]bool))
+}
+
+// generate builds the value of a schema, name being the property holding it, used to
+// guess realistic values.
+func (g *Generator) generate(schema *models.Schema, name string, visited map[string]bool) interface{} {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != nil && len(*schema.Ref) > 0 {
+		return g.generateRef(schema, name, visited)
+	}
+	return g.generateSchema(g.mergeAllOf(schema, make(map[string]bool)), name, visited)
+}
+
+func (g *Generator) generateRef(schema *models.Schema, name string, visited map[string]bool) interface{} {
+	ref := *schema.Ref
+	if visited[ref] {
+		// recursive definition, stop here
+		return nil
+	}
+	def, err := g.resolver.Schema(schema)
+	if err != nil {
+		logrus.Warn(err)
+		return nil
+	}
+	visited[ref] = true
+	defer delete(visited, ref)
+	def = g.mergeAllOf(def, map[string]bool{ref: true})
+	if def.Discriminator == nil || len(*def.Discriminator) == 0 {
+		return g.generate(def, name, visited)
+	}
+	base := schema.GetRefName()
+	if subtype := g.subtype(base); subtype != base {
+		return g.generate(&models.Schema{Ref: Pointer(definitionsRef + subtype)}, name, visited)
+	}
+	value := g.generateSchema(def, name, visited)
+	if obj, ok := value.(map[string]interface{}); ok {
+		obj[*def.Discriminator] = base
+	}
+	return value
+}
+
+func (g *Generator) generateSchema(schema *models.Schema, name string, visited map[string]bool) interface{} {
+	if schema.Default != nil {
+		return schema.Default
+	}
+	if schema.Enum != nil && len(*schema.Enum) > 0 {
+		return enumValue((*schema.Enum)[0])
+	}
+	switch schemaType(schema) {
+	case "object":
+		return g.generateObject(schema, visited)
+	case "array":
+		return g.generateArray(schema, name, visited)
+	case "string", "file":
+		return g.generateString(schema, name)
+	case "integer":
+		return g.generateNumber(schema, name, true)
+	case "number":
+		return g.generateNumber(schema, name, false)
+	case "boolean":
+		return g.data == DataRealistic && g.random().Intn(2) == 1
+	}
+	return nil
+}
+
+func (g *Generator) generateString(schema *models.Schema, name string) string {
+	value := defaultString(schema.Format)
+	if g.data == DataRealistic {
+		format := ""
+		if schema.Format != nil {
+			format = *schema.Format
+		}
+		value = fakeString(g.random(), name, format)
+	}
+	value = fitString(value, schema.Restrictions)
+	if schema.Pattern == nil {
+		return value
+	}
+	if re := compilePattern(*schema.Pattern); re == nil || re.MatchString(value) {
+		return value
+	}
+	rnd := g.random()
+	if g.data != DataRealistic {
+		// default values only depend on the pattern
+		rnd = rand.New(rand.NewSource(hashSeed(*schema.Pattern)))
+	}
+	if matching, ok := patternString(rnd, *schema.Pattern, schema.Restrictions); ok {
+		return matching
+	}
+	return value
+}
+
+// generateNumber returns an int64 for integers and a float64 for numbers, or a json.Number
+// holding the exact value when they would round it.
+func (g *Generator) generateNumber(schema *models.Schema, name string, integer bool) interface{} {
+	value := 0.0
+	if g.data == DataRealistic {
+		value = fakeNumber(g.random(), name, integer)
+	}
+	return numberValue(fitNumber(value, schema.Restrictions, integer), integer)
+}
+
+// numberValue returns an int64 for integers, a float64 when it is exact, a json.Number otherwise.
+func numberValue(v *big.Rat, integer bool) interface{} {
+	if integer && v.IsInt() && v.Num().IsInt64() {
+		return v.Num().Int64()
+	}
+	f, _ := v.Float64()
+	if exact, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64)); ok && exact.Cmp(v) == 0 {
+		return f
+	}
+	return json.Number(decimalString(v))
+}
+
+// generateArray fills an array within its minItems and maxItems. Tuple items, a list of
+// positional schemas, generate one item per schema, followed by additionalItems ones when it
+// is a schema and minItems asks for more.
+func (g *Generator) generateArray(schema *models.Schema, name string, visited map[string]bool) []interface{} {
+	arr := make([]interface{}, 0)
+	items := schema.GetItems()
+	if len(items) == 0 {
+		return arr
+	}
+	length := g.length
+	if g.limit != nil {
+		length = *g.limit
+	}
+	if length > maxArrayLength {
+		length = maxArrayLength
+	}
+	tuple := schema.HasTupleItems()
+	var additional *models.Schema
+	if tuple {
+		length = len(items)
+		additional = schema.GetAdditionalItems()
+	}
+	if schema.MinItems != nil && length < *schema.MinItems {
+		length = *schema.MinItems
+	}
+	if schema.MaxItems != nil && length > *schema.MaxItems {
+		length = *schema.MaxItems
+	}
+	if tuple && additional == nil && length > len(items) {
+		length = len(items)
+	}
+	// nested arrays are not limited, the items are not filled with the values
+	inner := g.withoutLimit().withoutValues()
+	unique := schema.UniqueItems != nil && *schema.UniqueItems
+	seen := make(map[string]bool)
+	for i := 0; i < length; i++ {
+		item := &items[0]
+		if tuple && i < len(items) {
+			item = &items[i]
+		} else if tuple {
+			item = additional
+		}
+		value := inner.generate(item, name, visited)
+		if value != nil && unique {
+			value = inner.uniqueItem(item, name, visited, value, seen)
+		}
+		if value == nil {
+			// recursive definition, or no distinct value left
+			break
+		}
+		arr = append(arr, value)
+	}
+	return arr
+}
+
+// uniqueItem returns the value when it is not in the array yet, otherwise another value of the
+// schema, nil when none is found. The other values are the enum values, then realistic ones,
+// default values being all alike.
+func (g *Generator) uniqueItem(schema *models.Schema, name string, visited map[string]bool, value interface{}, seen map[string]bool) interface{} {
+	candidates := g.enumValues(schema)
+	retry := g.WithData(DataRealistic)
+	for attempt := 0; attempt < len(candidates)+uniqueAttempts && value != nil; attempt++ {
+		key, err := ToString(value)
+		if err != nil {
+			return value
+		}
+		if !seen[key] {
+			seen[key] = true
+			return value
+		}
+		if attempt < len(candidates) {
+			value = candidates[attempt]
+		} else {
+			value = retry.generate(schema, name, visited)
+		}
+	}
+	return nil
+}
+
+// enumValues returns the enum values of a schema, following its reference.
+func (g *Generator) enumValues(schema *models.Schema) []interface{} {
+	resolved, err := g.resolver.Schema(schema)
+	if err != nil {
+		return nil
+	}
+	resolved = g.mergeAllOf(resolved, make(map[string]bool))
+	if resolved.Enum == nil {
+		return nil
+	}
+	values := make([]interface{}, 0, len(*resolved.Enum))
+	for _, e := range *resolved.Enum {
+		values = append(values, enumValue(e))
+	}
+	return values
+}
+
+// random returns the source of the realistic values, a time seeded one when no seed was given.
+func (g *Generator) random() *rand.Rand {
+	if g.rnd == nil {
+		return rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return g.rnd
+}
+
+func (g *Generator) generateObject(schema *models.Schema, visited map[string]bool) interface{} {
+	obj := make(map[string]interface{})
+	if schema.Properties != nil {
+		listing := g.listingProperty(schema)
+		for _, name := range sortedKeys(*schema.Properties) {
+			prop := (*schema.Properties)[name]
+			if raw, ok := g.value(name); ok && prop.Ref == nil {
+				if value, violation := coerce(raw, prop.TypeStruct); violation == nil {
+					obj[name] = value
+					continue
+				}
+			}
+			// only the root object is filled with the values
+			pg := g.withoutValues()
+			if name != listing {
+				pg = pg.withoutLimit()
+			}
+			if value := pg.generate(&prop, name, visited); value != nil {
+				obj[name] = value
+			}
+		}
+	}
+	return obj
+}
+
+// listingProperty returns the only array property of an object generated with a limit, empty
+// when there is none or several of them.
+func (g *Generator) listingProperty(schema *models.Schema) string {
+	if g.limit == nil {
+		return ""
+	}
+	listing := ""
+	for name, prop := range *schema.Properties {
+		resolved, err := g.resolver.Schema(&prop)
+		if err != nil || schemaType(g.mergeAllOf(resolved, make(map[string]bool))) != "array" {
+			continue
+		}
+		if len(listing) > 0 {
+			return ""
+		}
+		listing = name
+	}
+	return listing
+}
+
+func schemaType(schema *models.Schema) string {
+	if schema.Type != nil {
+		return *schema.Type
+	}
+	if schema.Properties != nil || schema.AllOf != nil || schema.AdditionalProperties != nil {
+		return "object"
+	}
+	if schema.Items != nil {
+		return "array"
+	}
+	return ""
+}
+
+func defaultString(format *string) string {
+	if format != nil {
+		switch *format {
+		case "date":
+			return "1970-01-01"
+		case "date-time":
+			return "1970-01-01T00:00:00Z"
+		case "email":
+			return "user@example.com"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		case "uri":
+			return "http://example.com"
+		case "hostname":
+			return "example.com"
+		case "ipv4":
+			return "0.0.0.0"
+		case "ipv6":
+			return "::"
+		}
+	}
+	return ""
+}
+
+// enumValue converts the numbers of an enum to the generated number types.
+func enumValue(value interface{}) interface{} {
+	if n, ok := value.(json.Number); ok {
+		if v, ok := new(big.Rat).SetString(string(n)); ok {
+			return numberValue(v, v.IsInt())
+		}
+	}
+	return value
+}
@@ -0,0 +1,115 @@
+package common
+
+import (
+	"encoding/json"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"reflect"
+	"testing"
+)
+
+const arraysSpec = `swagger: "2.0"
+info: {title: arrays, version: "1"}
+paths: {}
+definitions:
+  Tags: {type: array, items: {type: string}, minItems: 3, maxItems: 5}
+  Short: {type: array, items: {type: string}, maxItems: 2}
+  Sizes: {type: array, items: {$ref: "#/definitions/Size"}, uniqueItems: true, minItems: 3}
+  Size: {type: integer, enum: [1, 2, 3]}
+  Flags: {type: array, items: {type: boolean, enum: [true, false]}, uniqueItems: true, minItems: 2}
+  Point: {type: array, items: [{type: integer}, {type: string}]}
+  Path: {type: array, items: [{type: integer}, {type: string}], additionalItems: {type: boolean}, minItems: 4}
+  ClosedPath: {type: array, items: [{type: integer}, {type: string}], additionalItems: false, minItems: 4}
+  Page:
+    type: object
+    properties:
+      items: {type: array, items: {type: integer}}
+      total: {type: integer}
+      meta: {type: object, properties: {links: {type: string}, tags: {type: array, items: {type: string}}}}
+  Mixed:
+    type: object
+    properties:
+      a: {type: array, items: {type: integer}}
+      b: {type: array, items: {type: integer}}
+`
+
+func generate(t *testing.T, g *Generator, definition string) interface{} {
+	t.Helper()
+	ref := "#/definitions/" + definition
+	value := g.Generate(&models.Schema{Ref: &ref})
+	// compare the values as they are served
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generic, err := v2.DecodeJson(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return generic
+}
+
+func TestGenerateArrays(t *testing.T) {
+	doc, err := v2.LoadBytes([]byte(arraysSpec), "spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		definition  string
+		length      int
+		limit       int
+		want        string
+		wantLengths map[string]int
+	}{
+		{name: "minItems", definition: "Tags", want: `["","",""]`},
+		{name: "maxItems", definition: "Tags", length: 10, want: `["","","","",""]`},
+		{name: "maxItems below the default length", definition: "Short", length: 4, want: `["",""]`},
+		{name: "unique enum items", definition: "Sizes", want: `[1,2,3]`},
+		{name: "unique items beyond the enum", definition: "Sizes", length: 5, want: `[1,2,3]`},
+		{name: "unique boolean items", definition: "Flags", want: `[true,false]`},
+		{name: "tuple", definition: "Point", length: 5, want: `[0,""]`},
+		{name: "tuple with additional items", definition: "Path", want: `[0,"",false,false]`},
+		{name: "tuple without additional items", definition: "ClosedPath", want: `[0,""]`},
+		{name: "limit on the root array", definition: "Tags", limit: 4, want: `["","","",""]`},
+		{name: "limit within maxItems", definition: "Tags", limit: 20, want: `["","","","",""]`},
+		{name: "limit on the listing property", definition: "Page", limit: 3, wantLengths: map[string]int{"items": 3}},
+		{name: "limit without a single listing property", definition: "Mixed", limit: 3, wantLengths: map[string]int{"a": 1, "b": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(doc)
+			if tt.length > 0 {
+				g = g.WithArrayLength(tt.length)
+			}
+			if tt.limit > 0 {
+				g = g.WithLimit(tt.limit)
+			}
+			got := generate(t, g, tt.definition)
+			if len(tt.want) > 0 {
+				want, _ := v2.DecodeJson([]byte(tt.want))
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Generate(%s) = %v, want %s", tt.definition, got, tt.want)
+				}
+			}
+			object, _ := got.(map[string]interface{})
+			for property, length := range tt.wantLengths {
+				if arr, _ := object[property].([]interface{}); len(arr) != length {
+					t.Errorf("Generate(%s).%s has %d items, want %d", tt.definition, property, len(arr), length)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateLimitSkipsNestedArrays(t *testing.T) {
+	doc, err := v2.LoadBytes([]byte(arraysSpec), "spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := generate(t, NewGenerator(doc).WithLimit(4), "Page").(map[string]interface{})
+	meta, _ := page["meta"].(map[string]interface{})
+	if tags, _ := meta["tags"].([]interface{}); len(tags) != 1 {
+		t.Errorf("nested array has %d items, want the default length 1", len(tags))
+	}
+}
@@ -0,0 +1,37 @@
+package common
+
+import (
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"net/http"
+)
+
+// GenerateHeaders returns a value for every header declared by the response.
+func (g *Generator) GenerateHeaders(response *models.Response) http.Header {
+	headers := http.Header{}
+	if response == nil || response.Headers == nil {
+		return headers
+	}
+	// the limit applies to the body
+	g = g.withoutLimit()
+	for _, name := range sortedKeys(*response.Headers) {
+		header := (*response.Headers)[name]
+		value := g.generate(primitiveSchema(header.TypeStruct, header.Restrictions, header.Items), name, make(map[string]bool))
+		if value == nil {
+			continue
+		}
+		for _, v := range joinCollection(value, header.CollectionFormat, header.Items) {
+			headers.Add(name, v)
+		}
+	}
+	return headers
+}
+
+// primitiveSchema converts the type and restrictions of a header, parameter or items object to a schema.
+func primitiveSchema(t models.TypeStruct, r models.Restrictions, items *models.PrimitivesItems) *models.Schema {
+	schema := &models.Schema{Restrictions: r}
+	schema.TypeStruct = t
+	if items != nil {
+		schema.Items = primitiveSchema(items.TypeStruct, items.Restrictions, items.Items)
+	}
+	return schema
+}
@@ -0,0 +1,130 @@
+package common
+
+import (
+	"encoding/json"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/xeipuuv/gojsonschema"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const definitionsKey = "definitions"
+
+var (
+	compiledSchemas    sync.Map
+	definitionKeyRegex = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+)
+
+// CompileSchema converts a schema of the document, and every definition it references, to a
+// json schema and compiles it. Compiled schemas are cached, so operations sharing a schema
+// share its validator.
+func CompileSchema(doc *v2.Document, schema *models.Schema) (*gojsonschema.Schema, error) {
+	converted, err := jsonSchema(doc, schema)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(converted)
+	if err != nil {
+		return nil, err
+	}
+	key := string(data)
+	if compiled, ok := compiledSchemas.Load(key); ok {
+		return compiled.(*gojsonschema.Schema), nil
+	}
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(converted))
+	if err != nil {
+		return nil, err
+	}
+	compiledSchemas.Store(key, compiled)
+	return compiled, nil
+}
+
+// jsonSchema builds a standalone json schema: references to the document definitions are kept
+// and the definitions copied, any other reference is resolved and copied as a definition.
+func jsonSchema(doc *v2.Document, schema *models.Schema) (map[string]interface{}, error) {
+	root, err := toGeneric(schema)
+	if err != nil {
+		return nil, err
+	}
+	definitions := make(map[string]interface{})
+	if err = collectRefs(doc.Resolver(), root, definitions); err != nil {
+		return nil, err
+	}
+	result, ok := root.(map[string]interface{})
+	if !ok {
+		result = make(map[string]interface{})
+	}
+	if len(definitions) > 0 {
+		result[definitionsKey] = definitions
+	}
+	return result, nil
+}
+
+// collectRefs rewrites the references of the node to local definitions, resolving the ones
+// not yet collected.
+func collectRefs(resolver *v2.Resolver, node interface{}, definitions map[string]interface{}) error {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			key := definitionKey(ref)
+			v["$ref"] = "#/" + definitionsKey + "/" + key
+			if _, ok := definitions[key]; !ok {
+				value, err := resolver.Resolve(ref)
+				if err != nil {
+					return err
+				}
+				converted, err := toGeneric(value)
+				if err != nil {
+					return err
+				}
+				definitions[key] = converted
+				if err = collectRefs(resolver, converted, definitions); err != nil {
+					return err
+				}
+			}
+		}
+		normalizePattern(v)
+		for key, item := range v {
+			if key == "$ref" || key == "example" || key == "x-example" {
+				continue
+			}
+			if err := collectRefs(resolver, item, definitions); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := collectRefs(resolver, item, definitions); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// definitionKey names the local definition of a reference, keeping the document definition names.
+func definitionKey(ref string) string {
+	if strings.HasPrefix(ref, definitionsRef) && !strings.Contains(ref[len(definitionsRef):], "/") {
+		return ref[len(definitionsRef):]
+	}
+	return definitionKeyRegex.ReplaceAllString(ref, "_")
+}
+
+// normalizePattern replaces an ECMA-262 pattern go cannot compile by its approximation.
+func normalizePattern(schema map[string]interface{}) {
+	if pattern, ok := schema["pattern"].(string); ok {
+		if approximation, _, ok := v2.ApproximatePattern(pattern); ok {
+			schema["pattern"] = approximation
+		}
+	}
+}
+
+func toGeneric(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return v2.DecodeJson(data)
+}
@@ -0,0 +1,342 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"regexp"
+	"strings"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+var pathTemplateRegex = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Issue is a problem found in a document, located by a json pointer.
+type Issue struct {
+	Severity string `json:"severity"`
+	Location string `json:"location"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%-7s %s: %s: %s", i.Severity, i.Location, i.Rule, i.Message)
+}
+
+type pathOperation struct {
+	method string
+	op     *models.Operation
+}
+
+type linter struct {
+	doc    *v2.Document
+	issues []Issue
+}
+
+// Lint reports the schema violations of the document, its unresolved references and the
+// semantic problems the swagger 2.0 schema cannot express.
+func Lint(doc *v2.Document) []Issue {
+	l := &linter{doc: doc, issues: make([]Issue, 0)}
+	for _, e := range doc.Errors {
+		l.add(SeverityError, e.Field, "schema", e.Description)
+	}
+	for _, e := range doc.Resolver().Unresolved() {
+		l.add(SeverityError, e.Location, "unresolved-ref", fmt.Sprintf("cannot resolve %s: %s", e.Ref, e.Reason))
+	}
+	swagger := &doc.Swagger
+	if swagger.Definitions != nil {
+		for _, name := range sortedKeys(*swagger.Definitions) {
+			s := (*swagger.Definitions)[name]
+			l.schema(pointer("#", "definitions", name), &s, nil)
+		}
+	}
+	if swagger.Parameters != nil {
+		for _, name := range sortedKeys(*swagger.Parameters) {
+			p := (*swagger.Parameters)[name]
+			l.parameter(pointer("#", "parameters", name), &p)
+		}
+	}
+	if swagger.Responses != nil {
+		for _, name := range sortedKeys(*swagger.Responses) {
+			r := (*swagger.Responses)[name]
+			l.response(pointer("#", "responses", name), &r)
+		}
+	}
+	if swagger.Paths != nil {
+		l.paths(*swagger.Paths)
+	}
+	return l.issues
+}
+
+// HasErrors tells whether one of the issues is an error.
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *linter) add(severity string, location string, rule string, format string, args ...interface{}) {
+	l.issues = append(l.issues, Issue{Severity: severity, Location: location, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) paths(paths map[string]models.PathItem) {
+	operationIds := make(map[string]string)
+	for _, path := range sortedKeys(paths) {
+		item := paths[path]
+		location := pointer("#", "paths", path)
+		resolved, err := l.doc.Resolver().PathItem(&item)
+		if err != nil {
+			continue
+		}
+		pathParams := l.parameters(location+"/parameters", resolved.Parameters)
+		for _, o := range pathOperations(resolved) {
+			opLocation := location + "/" + o.method
+			if id := o.op.OperationId; id != nil {
+				if first, ok := operationIds[*id]; ok {
+					l.add(SeverityError, opLocation, "duplicate-operation-id", "operationId %s is already used by %s", *id, first)
+				} else {
+					operationIds[*id] = opLocation
+				}
+			}
+			params := overrideParameters(pathParams, l.parameters(opLocation+"/parameters", o.op.Parameters))
+			l.pathParameters(opLocation, path, params)
+			l.bodyParameters(opLocation, params)
+			if o.op.Responses != nil {
+				for _, code := range sortedKeys(*o.op.Responses) {
+					r := (*o.op.Responses)[code]
+					l.response(pointer(opLocation, "responses", code), &r)
+				}
+			}
+		}
+	}
+}
+
+// parameters resolves a parameter list, reporting the duplicated ones and checking the inline ones.
+func (l *linter) parameters(location string, params *[]models.Parameter) []models.Parameter {
+	resolved := make([]models.Parameter, 0)
+	if params == nil {
+		return resolved
+	}
+	seen := make(map[string]bool)
+	for i, p := range *params {
+		p := p
+		paramLocation := fmt.Sprintf("%s/%d", location, i)
+		r, err := l.doc.Resolver().Parameter(&p)
+		if err != nil {
+			continue
+		}
+		if key := r.GetLocationAndName(); seen[key] {
+			l.add(SeverityError, paramLocation, "duplicate-parameter", "parameter %s is declared more than once", key)
+		} else {
+			seen[key] = true
+		}
+		if p.Ref == nil {
+			l.parameter(paramLocation, r)
+		}
+		resolved = append(resolved, *r)
+	}
+	return resolved
+}
+
+// pathParameters matches the path parameters with the names of the path template.
+func (l *linter) pathParameters(location string, path string, params []models.Parameter) {
+	template := make(map[string]bool)
+	for _, match := range pathTemplateRegex.FindAllStringSubmatch(path, -1) {
+		template[match[1]] = true
+	}
+	declared := make(map[string]bool)
+	for _, p := range params {
+		if p.In == nil || *p.In != "path" || p.Name == nil {
+			continue
+		}
+		declared[*p.Name] = true
+		if !template[*p.Name] {
+			l.add(SeverityError, location, "unknown-path-parameter", "path parameter %s is not in the path template %s", *p.Name, path)
+		}
+	}
+	for _, name := range sortedKeys(template) {
+		if !declared[name] {
+			l.add(SeverityError, location, "missing-path-parameter", "path template parameter {%s} has no in: path parameter", name)
+		}
+	}
+}
+
+func (l *linter) bodyParameters(location string, params []models.Parameter) {
+	op := &models.Operation{Parameters: &params}
+	bodies, forms := len(op.GetBodyParameters()), len(op.GetFormDataParameters())
+	if bodies > 1 {
+		l.add(SeverityError, location, "multiple-body-parameters", "an operation can have only one body parameter, found %d", bodies)
+	}
+	if bodies > 0 && forms > 0 {
+		l.add(SeverityError, location, "body-and-formdata", "body and formData parameters cannot be used together")
+	}
+}
+
+func (l *linter) parameter(location string, p *models.Parameter) {
+	if p.In != nil && *p.In == "body" {
+		if p.Schema != nil {
+			l.schema(location+"/schema", p.Schema, nil)
+		}
+		return
+	}
+	l.primitiveDefault(location, p.TypeStruct, p.Restrictions, p.Items)
+}
+
+func (l *linter) response(location string, r *models.Response) {
+	if r.Ref != nil {
+		return
+	}
+	if r.Schema != nil {
+		l.schema(location+"/schema", r.Schema, nil)
+	}
+	if r.Headers != nil {
+		for _, name := range sortedKeys(*r.Headers) {
+			h := (*r.Headers)[name]
+			l.primitiveDefault(pointer(location, "headers", name), h.TypeStruct, h.Restrictions, h.Items)
+		}
+	}
+}
+
+// primitiveDefault checks the defaults of a parameter, a header and their items.
+func (l *linter) primitiveDefault(location string, t models.TypeStruct, r models.Restrictions, items *models.PrimitivesItems) {
+	if t.Type != nil && *t.Type == "file" {
+		return
+	}
+	l.defaultValue(location, primitiveSchema(t, r, items))
+	l.pattern(location, r.Pattern)
+	if items != nil {
+		l.primitiveDefault(location+"/items", items.TypeStruct, items.Restrictions, items.Items)
+	}
+}
+
+// schema checks a schema and its subschemas, the members of an allOf inheriting the
+// properties of the composed schema.
+func (l *linter) schema(location string, s *models.Schema, inherited map[string]bool) {
+	if s == nil || s.Ref != nil {
+		return
+	}
+	properties := l.propertyNames(s, make(map[string]bool))
+	if s.Required != nil {
+		for _, name := range *s.Required {
+			if !properties[name] && !inherited[name] {
+				l.add(SeverityWarning, location, "undefined-required-property", "required property %s is not in the properties", name)
+			}
+		}
+	}
+	l.defaultValue(location, s)
+	l.pattern(location, s.Pattern)
+	if s.Properties != nil {
+		for _, name := range sortedKeys(*s.Properties) {
+			prop := (*s.Properties)[name]
+			l.schema(pointer(location, "properties", name), &prop, nil)
+		}
+	}
+	if items := s.GetItems(); len(items) == 1 {
+		l.schema(location+"/items", &items[0], nil)
+	} else {
+		for i := range items {
+			l.schema(fmt.Sprintf("%s/items/%d", location, i), &items[i], nil)
+		}
+	}
+	if s.AllOf != nil {
+		for i := range *s.AllOf {
+			l.schema(fmt.Sprintf("%s/allOf/%d", location, i), &(*s.AllOf)[i], properties)
+		}
+	}
+	if additional, ok := s.AdditionalProperties.(map[string]interface{}); ok {
+		data, _ := json.Marshal(additional)
+		schema := &models.Schema{}
+		if json.Unmarshal(data, schema) == nil {
+			l.schema(location+"/additionalProperties", schema, nil)
+		}
+	}
+}
+
+// propertyNames collects the properties of the schema and of the schemas it is composed of.
+func (l *linter) propertyNames(s *models.Schema, seen map[string]bool) map[string]bool {
+	names := make(map[string]bool)
+	if s.Ref != nil {
+		if seen[*s.Ref] {
+			return names
+		}
+		seen[*s.Ref] = true
+		resolved, err := l.doc.Resolver().Schema(s)
+		if err != nil {
+			return names
+		}
+		s = resolved
+	}
+	if s.Properties != nil {
+		for name := range *s.Properties {
+			names[name] = true
+		}
+	}
+	if s.AllOf != nil {
+		for i := range *s.AllOf {
+			for name := range l.propertyNames(&(*s.AllOf)[i], seen) {
+				names[name] = true
+			}
+		}
+	}
+	return names
+}
+
+// defaultValue checks the default of a schema against the schema itself.
+func (l *linter) defaultValue(location string, s *models.Schema) {
+	if s.Default == nil {
+		return
+	}
+	compiled, err := CompileSchema(l.doc, s)
+	if err != nil {
+		return
+	}
+	value, err := toGeneric(s.Default)
+	if err != nil {
+		return
+	}
+	display, _ := json.Marshal(value)
+	for _, v := range checkSchema(compiled, value, "") {
+		l.add(SeverityError, location+"/default", "invalid-default", "default %s breaks %s: %s", display, v.Rule, v.Message)
+	}
+}
+
+// pattern warns about the ECMA-262 patterns go regular expressions can only approximate, the
+// values then being checked and generated against the approximation.
+func (l *linter) pattern(location string, pattern *string) {
+	if pattern == nil {
+		return
+	}
+	if approximation, exact, ok := v2.ApproximatePattern(*pattern); ok && !exact {
+		l.add(SeverityWarning, location+"/pattern", "approximated-pattern", "pattern %s uses lookarounds or backreferences go lacks, values are checked against %s", *pattern, approximation)
+	}
+}
+
+func pathOperations(item *models.PathItem) []pathOperation {
+	operations := make([]pathOperation, 0)
+	for _, o := range []pathOperation{
+		{"get", item.Get}, {"put", item.Put}, {"post", item.Post}, {"delete", item.Delete},
+		{"options", item.Options}, {"head", item.Head}, {"patch", item.Patch},
+	} {
+		if o.op != nil {
+			operations = append(operations, o)
+		}
+	}
+	return operations
+}
+
+func pointer(location string, tokens ...string) string {
+	escaped := make([]string, 0, len(tokens)+1)
+	escaped = append(escaped, location)
+	for _, token := range tokens {
+		escaped = append(escaped, v2.EscapePointer(token))
+	}
+	return strings.Join(escaped, "/")
+}
@@ -0,0 +1,141 @@
+package common
+
+import (
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"testing"
+)
+
+const lintHeader = `swagger: "2.0"
+info: {title: lint, version: "1"}
+`
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         string
+		wantRule     string
+		wantLocation string
+		wantSeverity string
+	}{
+		{
+			name: "duplicate operationId",
+			spec: `paths:
+  /a: {get: {operationId: list, responses: {"200": {description: ok}}}}
+  /b: {get: {operationId: list, responses: {"200": {description: ok}}}}
+`,
+			wantRule: "duplicate-operation-id", wantLocation: "#/paths/~1b/get", wantSeverity: SeverityError,
+		},
+		{
+			name: "path template parameter without a path parameter",
+			spec: `paths:
+  /pets/{id}: {get: {responses: {"200": {description: ok}}}}
+`,
+			wantRule: "missing-path-parameter", wantLocation: "#/paths/~1pets~1{id}/get", wantSeverity: SeverityError,
+		},
+		{
+			name: "path parameter missing from the template",
+			spec: `paths:
+  /pets:
+    get:
+      parameters: [{name: id, in: path, required: true, type: string}]
+      responses: {"200": {description: ok}}
+`,
+			wantRule: "unknown-path-parameter", wantLocation: "#/paths/~1pets/get", wantSeverity: SeverityError,
+		},
+		{
+			name: "unresolved reference",
+			spec: `paths:
+  /pets: {get: {responses: {"200": {description: ok, schema: {$ref: "#/definitions/Pet"}}}}}
+`,
+			wantRule: "unresolved-ref", wantLocation: "#/paths/~1pets/get/responses/200/schema", wantSeverity: SeverityError,
+		},
+		{
+			name: "body and formData",
+			spec: `paths:
+  /pets:
+    post:
+      parameters:
+        - {name: pet, in: body, schema: {type: object}}
+        - {name: name, in: formData, type: string}
+      responses: {"200": {description: ok}}
+`,
+			wantRule: "body-and-formdata", wantLocation: "#/paths/~1pets/post", wantSeverity: SeverityError,
+		},
+		{
+			name: "duplicate parameter",
+			spec: `paths:
+  /pets:
+    get:
+      parameters:
+        - {name: tag, in: query, type: string}
+        - {name: tag, in: query, type: integer}
+      responses: {"200": {description: ok}}
+`,
+			wantRule: "duplicate-parameter", wantLocation: "#/paths/~1pets/get/parameters/1", wantSeverity: SeverityError,
+		},
+		{
+			name: "undefined required property",
+			spec: `paths: {}
+definitions:
+  Pet: {type: object, required: [name], properties: {id: {type: integer}}}
+`,
+			wantRule: "undefined-required-property", wantLocation: "#/definitions/Pet", wantSeverity: SeverityWarning,
+		},
+		{
+			name: "invalid schema default",
+			spec: `paths: {}
+definitions:
+  Age: {type: integer, minimum: 0, default: -1}
+`,
+			wantRule: "invalid-default", wantLocation: "#/definitions/Age/default", wantSeverity: SeverityError,
+		},
+		{
+			name: "invalid parameter default",
+			spec: `paths:
+  /pets:
+    get:
+      parameters: [{name: size, in: query, type: integer, enum: [1, 2], default: 3}]
+      responses: {"200": {description: ok}}
+`,
+			wantRule: "invalid-default", wantLocation: "#/paths/~1pets/get/parameters/0/default", wantSeverity: SeverityError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := v2.LoadBytes([]byte(lintHeader+tt.spec), "spec.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			issues := Lint(doc)
+			for _, issue := range issues {
+				if issue.Rule == tt.wantRule && issue.Location == tt.wantLocation {
+					if issue.Severity != tt.wantSeverity {
+						t.Errorf("%s severity = %s, want %s", tt.wantRule, issue.Severity, tt.wantSeverity)
+					}
+					return
+				}
+			}
+			t.Errorf("Lint() = %v, want a %s issue at %s", issues, tt.wantRule, tt.wantLocation)
+		})
+	}
+}
+
+func TestLintCleanDocument(t *testing.T) {
+	spec := lintHeader + `paths:
+  /pets/{id}:
+    parameters: [{name: id, in: path, required: true, type: integer}]
+    get:
+      operationId: getPet
+      parameters: [{name: id, in: path, required: true, type: string}]
+      responses: {"200": {description: ok, schema: {$ref: "#/definitions/Pet"}}}
+definitions:
+  Pet: {type: object, required: [id], properties: {id: {type: integer, default: 1}}}
+`
+	doc, err := v2.LoadBytes([]byte(spec), "spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues := Lint(doc); len(issues) > 0 {
+		t.Errorf("Lint() = %v, want no issue", issues)
+	}
+}
@@ -0,0 +1,115 @@
+package common
+
+import (
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"strconv"
+	"strings"
+)
+
+const defaultMediaType = "application/json"
+
+type mediaRange struct {
+	mediaType string
+	subType   string
+	quality   float64
+}
+
+// Produces returns the media types of the operation, falling back to the document ones.
+func Produces(swagger *models.Swagger, op *models.Operation) []string {
+	if op != nil && op.Produces != nil && len(*op.Produces) > 0 {
+		return *op.Produces
+	}
+	if swagger != nil && swagger.Produces != nil && len(*swagger.Produces) > 0 {
+		return *swagger.Produces
+	}
+	return []string{defaultMediaType}
+}
+
+// Negotiate picks the offer with the highest quality in the Accept header, the most
+// specific matching range giving the quality of an offer. Ties keep the offers order.
+func Negotiate(accept string, offers []string) (string, bool) {
+	if len(offers) == 0 {
+		return "", false
+	}
+	if len(strings.TrimSpace(accept)) == 0 {
+		return offers[0], true
+	}
+	ranges := parseAccept(accept)
+	best, bestQuality := "", 0.0
+	for _, offer := range offers {
+		mediaType, subType := splitMediaType(offer)
+		quality, specificity := 0.0, -1
+		for _, r := range ranges {
+			s := r.matches(mediaType, subType)
+			if s > specificity {
+				quality, specificity = r.quality, s
+			}
+		}
+		if specificity >= 0 && quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+	return best, bestQuality > 0
+}
+
+// matches returns the specificity of the match, -1 when the range does not match.
+func (r mediaRange) matches(mediaType string, subType string) int {
+	switch {
+	case r.mediaType == "*" && r.subType == "*":
+		return 0
+	case r.mediaType == mediaType && r.subType == "*":
+		return 1
+	case r.mediaType == mediaType && r.subType == subType:
+		return 2
+	}
+	return -1
+}
+
+func parseAccept(accept string) []mediaRange {
+	ranges := make([]mediaRange, 0)
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType, subType := splitMediaType(params[0])
+		if len(mediaType) == 0 {
+			continue
+		}
+		r := mediaRange{mediaType: mediaType, subType: subType, quality: 1}
+		for _, param := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) == 2 && strings.TrimSpace(kv[0]) == "q" {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64); err == nil {
+					r.quality = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// splitMediaType returns the lower cased type and subtype, ignoring parameters.
+func splitMediaType(value string) (string, string) {
+	if i := strings.Index(value, ";"); i >= 0 {
+		value = value[:i]
+	}
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "*" {
+		return "*", "*"
+	}
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return value, ""
+	}
+	return parts[0], parts[1]
+}
+
+func sameMediaType(a string, b string) bool {
+	aType, aSub := splitMediaType(a)
+	bType, bSub := splitMediaType(b)
+	return aType == bType && aSub == bSub
+}
+
+func isJsonMediaType(mediaType string) bool {
+	t, sub := splitMediaType(mediaType)
+	return (t == "application" || t == "text") && (sub == "json" || strings.HasSuffix(sub, "+json"))
+}
@@ -0,0 +1,34 @@
+package common
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		offers []string
+		want   string
+		wantOk bool
+	}{
+		{name: "no accept header", accept: "", offers: []string{"application/xml", "application/json"}, want: "application/xml", wantOk: true},
+		{name: "no offers", accept: "application/json", wantOk: false},
+		{name: "exact match", accept: "application/json", offers: []string{"application/xml", "application/json"}, want: "application/json", wantOk: true},
+		{name: "case and parameters ignored", accept: "Application/JSON; charset=utf-8", offers: []string{"application/json"}, want: "application/json", wantOk: true},
+		{name: "wildcard keeps the offers order", accept: "*/*", offers: []string{"application/xml", "application/json"}, want: "application/xml", wantOk: true},
+		{name: "subtype wildcard", accept: "text/*", offers: []string{"application/json", "text/plain"}, want: "text/plain", wantOk: true},
+		{name: "highest quality wins", accept: "application/json;q=0.5, application/xml;q=0.9", offers: []string{"application/json", "application/xml"}, want: "application/xml", wantOk: true},
+		{name: "specific range overrides wildcard", accept: "*/*;q=0.8, application/json;q=0.1", offers: []string{"application/json", "text/plain"}, want: "text/plain", wantOk: true},
+		{name: "zero quality refuses", accept: "application/json;q=0", offers: []string{"application/json"}, wantOk: false},
+		{name: "zero quality excludes from wildcard", accept: "*/*, application/xml;q=0", offers: []string{"application/xml", "application/json"}, want: "application/json", wantOk: true},
+		{name: "invalid quality defaults to one", accept: "application/xml;q=high, application/json;q=0.5", offers: []string{"application/json", "application/xml"}, want: "application/xml", wantOk: true},
+		{name: "no match", accept: "image/png", offers: []string{"application/json"}, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Negotiate(tt.accept, tt.offers)
+			if ok != tt.wantOk || (ok && got != tt.want) {
+				t.Errorf("Negotiate(%q, %v) = %q, %v, want %q, %v", tt.accept, tt.offers, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
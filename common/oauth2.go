@@ -0,0 +1,321 @@
+package common
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// OAuth2Path is the default path the mock authorization server is mounted on.
+	OAuth2Path = "/oauth2"
+
+	flowImplicit    = "implicit"
+	flowPassword    = "password"
+	flowApplication = "application"
+	flowAccessCode  = "accessCode"
+
+	oauth2Issuer    = "go-swagger-mock"
+	tokenLifetime   = time.Hour
+	codeLifetime    = 10 * time.Minute
+	jwtHeader       = `{"alg":"HS256","typ":"JWT"}`
+	tokenTypeBearer = "bearer"
+)
+
+// AuthServer is a mock oauth2 authorization server for the oauth2 flows of the security definitions.
+// It approves every request and issues HS256 signed jwts holding the granted scopes.
+type AuthServer struct {
+	secret []byte
+	// scopes lists the scopes declared for every flow.
+	scopes map[string]map[string]bool
+	mu     sync.Mutex
+	codes  map[string]authorizationCode
+}
+
+type authorizationCode struct {
+	clientId    string
+	redirectUri string
+	scopes      []string
+	expires     time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+type oauth2Error struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+type jwtClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	Scope     string `json:"scope"`
+}
+
+// NewAuthServer creates the authorization server for the oauth2 definitions of the document.
+// Tokens are signed with the secret, a random one when empty.
+func NewAuthServer(swagger *models.Swagger, secret string) (*AuthServer, error) {
+	s := &AuthServer{secret: []byte(secret), scopes: make(map[string]map[string]bool), codes: make(map[string]authorizationCode)}
+	if len(s.secret) == 0 {
+		s.secret = make([]byte, 32)
+		if _, err := rand.Read(s.secret); err != nil {
+			return nil, err
+		}
+	}
+	if swagger.SecurityDefinitions != nil {
+		for _, definition := range *swagger.SecurityDefinitions {
+			if securityType(definition) != securityOAuth2 || definition.Flow == nil {
+				continue
+			}
+			if s.scopes[*definition.Flow] == nil {
+				s.scopes[*definition.Flow] = make(map[string]bool)
+			}
+			if definition.Scopes != nil {
+				for scope := range *definition.Scopes {
+					s.scopes[*definition.Flow][scope] = true
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// Flows returns the sorted oauth2 flows declared by the document.
+func (s *AuthServer) Flows() []string {
+	flows := make([]string, 0, len(s.scopes))
+	for flow := range s.scopes {
+		flows = append(flows, flow)
+	}
+	sort.Strings(flows)
+	return flows
+}
+
+// Register mounts the authorize and token endpoints under the path. It must be called after
+// the spec routes are registered, so a conflict with them is returned instead of panicking.
+func (s *AuthServer) Register(engine *gin.Engine, path string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("the oauth2 endpoints under %s conflict with the spec paths: %v", path, r)
+		}
+	}()
+	group := engine.Group(path)
+	group.GET("/authorize", s.authorize)
+	group.POST("/token", s.token)
+	return nil
+}
+
+// Inspect is a TokenInspector accepting the unexpired tokens signed by the server.
+func (s *AuthServer) Inspect(token string) ([]string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, s.sign(parts[0]+"."+parts[1])) {
+		return nil, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, false
+	}
+	claims := jwtClaims{}
+	if err = json.Unmarshal(payload, &claims); err != nil || time.Now().Unix() >= claims.ExpiresAt {
+		return nil, false
+	}
+	return jwtScopes(payload), true
+}
+
+// authorize approves the implicit (response_type=token) and accessCode (response_type=code)
+// requests, redirecting to the redirect_uri with the token or the code.
+func (s *AuthServer) authorize(ctx *gin.Context) {
+	redirect, err := url.Parse(ctx.Query("redirect_uri"))
+	if err != nil || !redirect.IsAbs() {
+		ctx.JSON(http.StatusBadRequest, oauth2Error{Error: "invalid_request", Description: "redirect_uri must be an absolute url"})
+		return
+	}
+	params := url.Values{}
+	if state, ok := ctx.GetQuery("state"); ok {
+		params.Set("state", state)
+	}
+	flow := ""
+	switch ctx.Query("response_type") {
+	case "token":
+		flow = flowImplicit
+	case "code":
+		flow = flowAccessCode
+	default:
+		params.Set("error", "unsupported_response_type")
+		s.redirect(ctx, redirect, params, false)
+		return
+	}
+	scopes, oauthErr := s.grant(flow, ctx.Query("scope"))
+	if oauthErr != nil {
+		params.Set("error", oauthErr.Error)
+		params.Set("error_description", oauthErr.Description)
+		s.redirect(ctx, redirect, params, flow == flowImplicit)
+		return
+	}
+	clientId := ctx.Query("client_id")
+	if flow == flowAccessCode {
+		code := randomString()
+		s.mu.Lock()
+		for c, pending := range s.codes {
+			if time.Now().After(pending.expires) {
+				delete(s.codes, c)
+			}
+		}
+		s.codes[code] = authorizationCode{clientId: clientId, redirectUri: redirect.String(), scopes: scopes, expires: time.Now().Add(codeLifetime)}
+		s.mu.Unlock()
+		params.Set("code", code)
+		s.redirect(ctx, redirect, params, false)
+		return
+	}
+	token := s.issue(clientId, clientId, scopes)
+	params.Set("access_token", token.AccessToken)
+	params.Set("token_type", token.TokenType)
+	params.Set("expires_in", fmt.Sprint(token.ExpiresIn))
+	params.Set("scope", token.Scope)
+	s.redirect(ctx, redirect, params, true)
+}
+
+// token issues tokens for the password, client_credentials (application) and
+// authorization_code (accessCode) grants.
+func (s *AuthServer) token(ctx *gin.Context) {
+	clientId, _, ok := ctx.Request.BasicAuth()
+	if !ok {
+		clientId = ctx.PostForm("client_id")
+	}
+	switch ctx.PostForm("grant_type") {
+	case "password":
+		username := ctx.PostForm("username")
+		if len(username) == 0 || len(ctx.PostForm("password")) == 0 {
+			ctx.JSON(http.StatusBadRequest, oauth2Error{Error: "invalid_request", Description: "username and password are required"})
+			return
+		}
+		s.respond(ctx, flowPassword, username, clientId)
+	case "client_credentials":
+		if len(clientId) == 0 {
+			ctx.JSON(http.StatusUnauthorized, oauth2Error{Error: "invalid_client", Description: "client_id is required"})
+			return
+		}
+		s.respond(ctx, flowApplication, clientId, clientId)
+	case "authorization_code":
+		s.mu.Lock()
+		code, found := s.codes[ctx.PostForm("code")]
+		delete(s.codes, ctx.PostForm("code"))
+		s.mu.Unlock()
+		if !found || time.Now().After(code.expires) || code.redirectUri != ctx.PostForm("redirect_uri") {
+			ctx.JSON(http.StatusBadRequest, oauth2Error{Error: "invalid_grant", Description: "unknown, expired or mismatched authorization code"})
+			return
+		}
+		ctx.Header("Cache-Control", "no-store")
+		ctx.JSON(http.StatusOK, s.issue(code.clientId, code.clientId, code.scopes))
+	default:
+		ctx.JSON(http.StatusBadRequest, oauth2Error{Error: "unsupported_grant_type"})
+	}
+}
+
+func (s *AuthServer) respond(ctx *gin.Context, flow string, subject string, clientId string) {
+	scopes, err := s.grant(flow, ctx.PostForm("scope"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, err)
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, s.issue(subject, clientId, scopes))
+}
+
+// grant returns the requested scopes, or every scope of the flow when none is requested.
+func (s *AuthServer) grant(flow string, requested string) ([]string, *oauth2Error) {
+	declared, ok := s.scopes[flow]
+	if !ok {
+		if flow == flowImplicit || flow == flowAccessCode {
+			return nil, &oauth2Error{Error: "unsupported_response_type", Description: fmt.Sprintf("no %s flow is declared", flow)}
+		}
+		return nil, &oauth2Error{Error: "unsupported_grant_type", Description: fmt.Sprintf("no %s flow is declared", flow)}
+	}
+	scopes := strings.Fields(requested)
+	if len(scopes) == 0 {
+		for scope := range declared {
+			scopes = append(scopes, scope)
+		}
+		sort.Strings(scopes)
+	}
+	for _, scope := range scopes {
+		if !declared[scope] {
+			return nil, &oauth2Error{Error: "invalid_scope", Description: fmt.Sprintf("scope %s is not declared for the %s flow", scope, flow)}
+		}
+	}
+	return scopes, nil
+}
+
+func (s *AuthServer) issue(subject string, clientId string, scopes []string) tokenResponse {
+	now := time.Now()
+	claims, _ := json.Marshal(jwtClaims{
+		Issuer:    oauth2Issuer,
+		Subject:   subject,
+		Audience:  clientId,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(tokenLifetime).Unix(),
+		Scope:     strings.Join(scopes, " "),
+	})
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(jwtHeader)) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return tokenResponse{
+		AccessToken: unsigned + "." + base64.RawURLEncoding.EncodeToString(s.sign(unsigned)),
+		TokenType:   tokenTypeBearer,
+		ExpiresIn:   int(tokenLifetime.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}
+}
+
+func (s *AuthServer) sign(data string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// redirect sends the params to the redirect uri, in its fragment for the implicit flow.
+func (s *AuthServer) redirect(ctx *gin.Context, redirect *url.URL, params url.Values, fragment bool) {
+	target := *redirect
+	if fragment {
+		target.Fragment = ""
+		target.RawFragment = ""
+		ctx.Redirect(http.StatusFound, target.String()+"#"+params.Encode())
+		return
+	}
+	query := target.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	target.RawQuery = query.Encode()
+	ctx.Redirect(http.StatusFound, target.String())
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		logrus.Error(err)
+	}
+	return hex.EncodeToString(b)
+}
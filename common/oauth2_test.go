@@ -0,0 +1,242 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const oauth2Spec = `swagger: "2.0"
+info: {title: pets, version: "1"}
+securityDefinitions:
+  implicit: {type: oauth2, flow: implicit, authorizationUrl: "https://example.com/authorize", scopes: {read: read, write: write}}
+  code: {type: oauth2, flow: accessCode, authorizationUrl: "https://example.com/authorize", tokenUrl: "https://example.com/token", scopes: {read: read}}
+  password: {type: oauth2, flow: password, tokenUrl: "https://example.com/token", scopes: {admin: admin}}
+  application: {type: oauth2, flow: application, tokenUrl: "https://example.com/token", scopes: {sync: sync}}
+paths: {}
+`
+
+func newAuthServer(t *testing.T) (*AuthServer, *gin.Engine) {
+	t.Helper()
+	doc, err := v2.LoadBytes([]byte(oauth2Spec), "spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Valid() {
+		t.Fatalf("unexpected errors %v", doc.Errors)
+	}
+	s, err := NewAuthServer(&doc.Swagger, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	if err = s.Register(engine, OAuth2Path); err != nil {
+		t.Fatal(err)
+	}
+	return s, engine
+}
+
+func authorize(engine *gin.Engine, query url.Values) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, OAuth2Path+"/authorize?"+query.Encode(), nil))
+	return w
+}
+
+func requestToken(engine *gin.Engine, form url.Values) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, OAuth2Path+"/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+// redirectParams returns the params of the redirect, from its fragment for the implicit flow.
+func redirectParams(t *testing.T, w *httptest.ResponseRecorder) url.Values {
+	t.Helper()
+	if w.Code != http.StatusFound {
+		t.Fatalf("status = %d, want a redirect (%s)", w.Code, w.Body.String())
+	}
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(location.Fragment) > 0 {
+		params, err := url.ParseQuery(location.Fragment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return params
+	}
+	return location.Query()
+}
+
+func TestAuthServerFlows(t *testing.T) {
+	s, engine := newAuthServer(t)
+	if want := []string{flowAccessCode, flowApplication, flowImplicit, flowPassword}; !reflect.DeepEqual(s.Flows(), want) {
+		t.Errorf("Flows() = %v, want %v", s.Flows(), want)
+	}
+	tests := []struct {
+		name       string
+		authorize  url.Values
+		token      url.Values
+		want       int
+		wantError  string
+		wantScopes []string
+	}{
+		{name: "implicit", authorize: url.Values{"response_type": {"token"}, "scope": {"write"}}, wantScopes: []string{"write"}},
+		{name: "implicit grants every declared scope", authorize: url.Values{"response_type": {"token"}}, wantScopes: []string{"read", "write"}},
+		{name: "implicit undeclared scope", authorize: url.Values{"response_type": {"token"}, "scope": {"admin"}}, wantError: "invalid_scope"},
+		{name: "unsupported response type", authorize: url.Values{"response_type": {"id_token"}}, wantError: "unsupported_response_type"},
+		{name: "password", token: url.Values{"grant_type": {"password"}, "username": {"jane"}, "password": {"secret"}}, want: http.StatusOK, wantScopes: []string{"admin"}},
+		{name: "password without credentials", token: url.Values{"grant_type": {"password"}, "username": {"jane"}}, want: http.StatusBadRequest, wantError: "invalid_request"},
+		{name: "password undeclared scope", token: url.Values{"grant_type": {"password"}, "username": {"jane"}, "password": {"secret"}, "scope": {"read"}}, want: http.StatusBadRequest, wantError: "invalid_scope"},
+		{name: "application", token: url.Values{"grant_type": {"client_credentials"}, "client_id": {"app"}}, want: http.StatusOK, wantScopes: []string{"sync"}},
+		{name: "application undeclared scope", token: url.Values{"grant_type": {"client_credentials"}, "client_id": {"app"}, "scope": {"admin"}}, want: http.StatusBadRequest, wantError: "invalid_scope"},
+		{name: "application without client", token: url.Values{"grant_type": {"client_credentials"}}, want: http.StatusUnauthorized, wantError: "invalid_client"},
+		{name: "unknown grant", token: url.Values{"grant_type": {"refresh_token"}}, want: http.StatusBadRequest, wantError: "unsupported_grant_type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := make(map[string]string)
+			if tt.authorize != nil {
+				tt.authorize.Set("redirect_uri", "https://client.example.com/callback")
+				tt.authorize.Set("state", "xyz")
+				values := redirectParams(t, authorize(engine, tt.authorize))
+				if values.Get("state") != "xyz" {
+					t.Errorf("state = %q, want xyz", values.Get("state"))
+				}
+				params = map[string]string{"access_token": values.Get("access_token"), "error": values.Get("error")}
+			} else {
+				w := requestToken(engine, tt.token)
+				if w.Code != tt.want {
+					t.Fatalf("status = %d, want %d (%s)", w.Code, tt.want, w.Body.String())
+				}
+				body := struct {
+					tokenResponse
+					oauth2Error
+				}{}
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatal(err)
+				}
+				params["access_token"], params["error"] = body.AccessToken, body.Error
+			}
+			if params["error"] != tt.wantError {
+				t.Fatalf("error = %q, want %q", params["error"], tt.wantError)
+			}
+			if len(tt.wantError) > 0 {
+				return
+			}
+			scopes, ok := s.Inspect(params["access_token"])
+			if !ok {
+				t.Fatalf("Inspect(%q) rejected the issued token", params["access_token"])
+			}
+			if !reflect.DeepEqual(scopes, tt.wantScopes) {
+				t.Errorf("scopes = %v, want %v", scopes, tt.wantScopes)
+			}
+		})
+	}
+}
+
+func TestAuthServerAuthorizationCode(t *testing.T) {
+	s, engine := newAuthServer(t)
+	callback := "https://client.example.com/callback?app=1"
+	issue := func() string {
+		params := redirectParams(t, authorize(engine, url.Values{"response_type": {"code"}, "client_id": {"app"}, "redirect_uri": {callback}}))
+		if params.Get("app") != "1" || len(params.Get("code")) == 0 {
+			t.Fatalf("redirect params = %v, want the code added to the callback query", params)
+		}
+		return params.Get("code")
+	}
+	exchange := func(code string, redirectUri string) *httptest.ResponseRecorder {
+		return requestToken(engine, url.Values{"grant_type": {"authorization_code"}, "code": {code}, "redirect_uri": {redirectUri}})
+	}
+
+	code := issue()
+	w := exchange(code, callback)
+	if w.Code != http.StatusOK {
+		t.Fatalf("exchange status = %d (%s)", w.Code, w.Body.String())
+	}
+	token := tokenResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &token); err != nil {
+		t.Fatal(err)
+	}
+	if scopes, ok := s.Inspect(token.AccessToken); !ok || !reflect.DeepEqual(scopes, []string{"read"}) {
+		t.Errorf("Inspect() = %v, %v, want [read], true", scopes, ok)
+	}
+	if w = exchange(code, callback); w.Code != http.StatusBadRequest {
+		t.Errorf("reused code status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	if w = exchange(issue(), "https://attacker.example.com/callback"); w.Code != http.StatusBadRequest {
+		t.Errorf("mismatched redirect_uri status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	code = issue()
+	s.mu.Lock()
+	expired := s.codes[code]
+	expired.expires = time.Now().Add(-time.Second)
+	s.codes[code] = expired
+	s.mu.Unlock()
+	if w = exchange(code, callback); w.Code != http.StatusBadRequest {
+		t.Errorf("expired code status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	if w = authorize(engine, url.Values{"response_type": {"code"}, "redirect_uri": {"/relative"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("relative redirect_uri status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAuthServerInspect(t *testing.T) {
+	s, _ := newAuthServer(t)
+	token := s.issue("jane", "app", []string{"read"}).AccessToken
+	parts := strings.Split(token, ".")
+	sign := func(claims string) string {
+		unsigned := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(claims))
+		return unsigned + "." + base64.RawURLEncoding.EncodeToString(s.sign(unsigned))
+	}
+	other, err := NewAuthServer(&models.Swagger{}, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{name: "issued", token: token, want: true},
+		{name: "tampered payload", token: parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"scope":"admin","exp":9999999999}`)) + "." + parts[2]},
+		{name: "tampered signature", token: parts[0] + "." + parts[1] + "." + base64.RawURLEncoding.EncodeToString([]byte("forged"))},
+		{name: "other secret", token: other.issue("jane", "app", []string{"read"}).AccessToken},
+		{name: "expired", token: sign(`{"scope":"read","exp":` + strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10) + `}`)},
+		{name: "unexpired", token: sign(`{"scope":"read","exp":` + strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10) + `}`), want: true},
+		{name: "malformed", token: "not-a-jwt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := s.Inspect(tt.token); ok != tt.want {
+				t.Errorf("Inspect() ok = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthServerRegisterConflict(t *testing.T) {
+	s, _ := newAuthServer(t)
+	engine := gin.New()
+	engine.POST(OAuth2Path+"/token", func(ctx *gin.Context) {})
+	err := s.Register(engine, OAuth2Path)
+	if err == nil || !strings.Contains(err.Error(), "conflict") {
+		t.Errorf("Register() = %v, want a route conflict", err)
+	}
+}
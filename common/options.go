@@ -0,0 +1,77 @@
+package common
+
+import (
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/sirupsen/logrus"
+)
+
+// ValidationExtension overrides the validation modes of the document, a path or an operation,
+// either for both directions ("x-mock-validation: lenient") or for each one
+// ("x-mock-validation: {request: off, response: strict}").
+const ValidationExtension = "x-mock-validation"
+
+// Options tunes the behaviour of the generated routes.
+type Options struct {
+	// LenientConsumes only logs a warning for request content types the operation does not consume.
+	LenientConsumes bool
+	// Security enforces the security requirements of the operations.
+	Security bool
+	// RequestValidation checks the requests against the operation parameters, ValidationStrict when empty.
+	RequestValidation ValidationMode
+	// ResponseValidation checks the mocked responses against their definition, ValidationOff when empty.
+	ResponseValidation ValidationMode
+	// Seed is mixed into the seed of every request, changing the generated values.
+	Seed int64
+	// Data selects the values of the generated responses, DataDefaults when empty.
+	Data DataMode
+	// ArrayLength is the number of items of the generated arrays, within their minItems and
+	// maxItems, 1 when 0.
+	ArrayLength int
+	// LimitParameter names the query parameter setting the number of items of the listing, the
+	// response array or the only array property of the response object, for the operations
+	// declaring it. None when empty.
+	LimitParameter string
+	// TokenInspector grants the scopes of oauth2 tokens, InspectJwtClaims when nil.
+	TokenInspector TokenInspector
+}
+
+// ForOperation returns the options of an operation, applying the validation extensions of
+// the document, then the path and then the operation.
+func (o Options) ForOperation(swagger *models.Swagger, item *models.PathItem, op *models.Operation) Options {
+	if len(o.RequestValidation) == 0 {
+		o.RequestValidation = ValidationStrict
+	}
+	if len(o.ResponseValidation) == 0 {
+		o.ResponseValidation = ValidationOff
+	}
+	for _, extensions := range []models.Extensions{swagger.Extensions, item.Extensions, op.Extensions} {
+		value, ok := extensions.Get(ValidationExtension)
+		if !ok {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			o.RequestValidation = o.validationMode(v, o.RequestValidation)
+			o.ResponseValidation = o.validationMode(v, o.ResponseValidation)
+		case map[string]interface{}:
+			if request, ok := v["request"].(string); ok {
+				o.RequestValidation = o.validationMode(request, o.RequestValidation)
+			}
+			if response, ok := v["response"].(string); ok {
+				o.ResponseValidation = o.validationMode(response, o.ResponseValidation)
+			}
+		default:
+			logrus.Warnf("ignoring %s: %v", ValidationExtension, value)
+		}
+	}
+	return o
+}
+
+func (o Options) validationMode(name string, fallback ValidationMode) ValidationMode {
+	mode, err := ParseValidationMode(name)
+	if err != nil || len(name) == 0 {
+		logrus.Warnf("ignoring %s: %q", ValidationExtension, name)
+		return fallback
+	}
+	return mode
+}
@@ -0,0 +1,167 @@
+package common
+
+import (
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"math/rand"
+	"regexp/syntax"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	// patternAttempts bounds the strings generated while looking for one within the length restrictions.
+	patternAttempts = 100
+	// patternRepeat is the number of repetitions allowed past the minimum of unbounded repeats,
+	// like * and +, before the length restrictions require more.
+	patternRepeat = 4
+	// printableFirst and printableLast bound the runes preferred for character classes.
+	printableFirst = 0x20
+	printableLast  = 0x7e
+)
+
+var parsedPatterns sync.Map
+
+// patternGenerator builds strings matching a parsed pattern.
+type patternGenerator struct {
+	rnd *rand.Rand
+	// extra is the number of repetitions an unbounded repeat may add to its minimum.
+	extra int
+	// stretch makes the repeats use every allowed repetition.
+	stretch bool
+}
+
+// patternString generates a string matching the pattern and, when possible, the length
+// restrictions. It returns false when the pattern cannot be parsed or matched.
+func patternString(rnd *rand.Rand, pattern string, r models.Restrictions) (string, bool) {
+	re := parsePattern(pattern)
+	compiled := compilePattern(pattern)
+	if re == nil || compiled == nil {
+		return "", false
+	}
+	// the allowed repetitions grow after too short stretched values, faster for long minimum
+	// lengths, and shrink after too long ones
+	step := 1
+	if r.MinLength != nil && *r.MinLength > patternAttempts/4 {
+		step = *r.MinLength / (patternAttempts / 4)
+	}
+	extra := patternRepeat
+	fallback, found := "", false
+	for attempt := 0; attempt < patternAttempts; attempt++ {
+		g := &patternGenerator{rnd: rnd, extra: extra, stretch: attempt%2 == 1}
+		var b strings.Builder
+		g.write(&b, re)
+		value := b.String()
+		if !compiled.MatchString(value) {
+			continue
+		}
+		length := utf8.RuneCountInString(value)
+		tooShort := r.MinLength != nil && length < *r.MinLength
+		tooLong := r.MaxLength != nil && length > *r.MaxLength
+		if !tooShort && !tooLong {
+			return value, true
+		}
+		if g.stretch && tooShort {
+			extra += step
+		} else if g.stretch && extra > 0 {
+			extra--
+		}
+		if !found {
+			fallback, found = value, true
+		}
+	}
+	return fallback, found
+}
+
+func parsePattern(pattern string) *syntax.Regexp {
+	if re, ok := parsedPatterns.Load(pattern); ok {
+		return re.(*syntax.Regexp)
+	}
+	var re *syntax.Regexp
+	if approximation, _, ok := v2.ApproximatePattern(pattern); ok {
+		if parsed, err := syntax.Parse(approximation, syntax.Perl); err == nil {
+			re = parsed.Simplify()
+		}
+	}
+	parsedPatterns.Store(pattern, re)
+	return re
+}
+
+func (g *patternGenerator) write(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(g.classRune(re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		b.WriteRune(rune('a' + g.rnd.Intn(26)))
+	case syntax.OpCapture:
+		g.write(b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.write(b, sub)
+		}
+	case syntax.OpAlternate:
+		g.write(b, re.Sub[g.rnd.Intn(len(re.Sub))])
+	case syntax.OpStar:
+		g.repeat(b, re.Sub[0], 0, -1)
+	case syntax.OpPlus:
+		g.repeat(b, re.Sub[0], 1, -1)
+	case syntax.OpQuest:
+		g.repeat(b, re.Sub[0], 0, 1)
+	case syntax.OpRepeat:
+		g.repeat(b, re.Sub[0], re.Min, re.Max)
+	}
+	// anchors, word boundaries and empty matches write nothing
+}
+
+// repeat writes the expression between min and max times, max being -1 for unbounded repeats.
+func (g *patternGenerator) repeat(b *strings.Builder, re *syntax.Regexp, min int, max int) {
+	if max < 0 || max > min+g.extra {
+		max = min + g.extra
+	}
+	count := max
+	if !g.stretch {
+		count = min + g.rnd.Intn(max-min+1)
+	}
+	for i := 0; i < count; i++ {
+		g.write(b, re)
+	}
+}
+
+// classRune picks a rune of a character class, given as range pairs, preferring printable ascii.
+func (g *patternGenerator) classRune(ranges []rune) rune {
+	printable := make([]rune, 0, len(ranges))
+	total := 0
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < printableFirst {
+			lo = printableFirst
+		}
+		if hi > printableLast {
+			hi = printableLast
+		}
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+			total += int(hi-lo) + 1
+		}
+	}
+	if total == 0 {
+		if len(ranges) == 0 {
+			return 'x'
+		}
+		return ranges[0]
+	}
+	n := g.rnd.Intn(total)
+	for i := 0; i < len(printable); i += 2 {
+		size := int(printable[i+1]-printable[i]) + 1
+		if n < size {
+			return printable[i] + rune(n)
+		}
+		n -= size
+	}
+	return printable[0]
+}
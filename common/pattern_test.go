@@ -0,0 +1,58 @@
+package common
+
+import (
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"math/rand"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestPatternString(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    string
+		minLength  int
+		maxLength  int
+		wantOk     bool
+		wantLength bool
+	}{
+		{name: "digits", pattern: `^\d{3}-\d{4}$`, wantOk: true, wantLength: true},
+		{name: "alternation", pattern: `^(cat|dog|bird)s?$`, wantOk: true, wantLength: true},
+		{name: "unanchored", pattern: `[A-Z]{2}`, wantOk: true, wantLength: true},
+		{name: "minimum length", pattern: `^[a-z]+$`, minLength: 40, wantOk: true, wantLength: true},
+		{name: "maximum length", pattern: `^[a-z]*$`, maxLength: 2, wantOk: true, wantLength: true},
+		{name: "length range", pattern: `^[a-f0-9]+(-[a-f0-9]+)*$`, minLength: 10, maxLength: 12, wantOk: true, wantLength: true},
+		{name: "unicode class", pattern: `^\p{Greek}{2,3}$`, wantOk: true, wantLength: true},
+		{name: "ecma lookahead", pattern: `^(?=.*\d)[a-z\d]{8}$`, wantOk: true, wantLength: true},
+		{name: "length out of reach", pattern: `^ab$`, minLength: 5, wantOk: true, wantLength: false},
+		{name: "invalid", pattern: `^(ab$`, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := models.Restrictions{}
+			if tt.minLength > 0 {
+				r.MinLength = &tt.minLength
+			}
+			if tt.maxLength > 0 {
+				r.MaxLength = &tt.maxLength
+			}
+			for seed := int64(0); seed < 20; seed++ {
+				value, ok := patternString(rand.New(rand.NewSource(seed)), tt.pattern, r)
+				if ok != tt.wantOk {
+					t.Fatalf("patternString(%q) ok = %v, want %v", tt.pattern, ok, tt.wantOk)
+				}
+				if !ok {
+					return
+				}
+				if !compilePattern(tt.pattern).MatchString(value) {
+					t.Errorf("patternString(%q) = %q does not match", tt.pattern, value)
+				}
+				length := utf8.RuneCountInString(value)
+				within := (r.MinLength == nil || length >= *r.MinLength) && (r.MaxLength == nil || length <= *r.MaxLength)
+				if within != tt.wantLength {
+					t.Errorf("patternString(%q) = %q, within the length restrictions %v, want %v", tt.pattern, value, within, tt.wantLength)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultResponseCode = "default"
+
+const (
+	// StatusHeader asks for the response declared with the given code, e.g. "X-Mock-Status: 404".
+	StatusHeader = "X-Mock-Status"
+	// PreferHeader is checked for a "code=404" preference when StatusHeader is missing.
+	PreferHeader = "Prefer"
+)
+
+var preferCodeRegex = regexp.MustCompile(`(?:^|[,;\s])code\s*=\s*"?(\w+)"?`)
+
+// RequestedStatus returns the response code asked by the caller, empty when none is.
+func RequestedStatus(req *http.Request) string {
+	if status := strings.TrimSpace(req.Header.Get(StatusHeader)); len(status) > 0 {
+		return status
+	}
+	for _, prefer := range req.Header.Values(PreferHeader) {
+		if match := preferCodeRegex.FindStringSubmatch(prefer); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// SelectResponse picks the response used for the mock. A requested code must be declared
+// by the operation, or be covered by its "default" response. Without one, the lowest 2xx
+// code is used, then "default", then the lowest declared code.
+func SelectResponse(doc *v2.Document, op *models.Operation, requested string) (int, *models.Response, error) {
+	if len(requested) > 0 {
+		return selectRequestedResponse(doc, op, requested)
+	}
+	if op == nil || op.Responses == nil || len(*op.Responses) == 0 {
+		return http.StatusOK, nil, nil
+	}
+	responses := *op.Responses
+	codes := declaredCodes(op)
+	for _, code := range codes {
+		if code >= 200 && code < 300 {
+			return code, lookupResponse(doc, responses[strconv.Itoa(code)]), nil
+		}
+	}
+	if response, ok := responses[defaultResponseCode]; ok {
+		return http.StatusOK, lookupResponse(doc, response), nil
+	}
+	if len(codes) > 0 {
+		return codes[0], lookupResponse(doc, responses[strconv.Itoa(codes[0])]), nil
+	}
+	return http.StatusOK, nil, nil
+}
+
+func selectRequestedResponse(doc *v2.Document, op *models.Operation, requested string) (int, *models.Response, error) {
+	code, err := strconv.Atoi(requested)
+	if err != nil || code < 100 || code > 599 {
+		return 0, nil, fmt.Errorf("invalid requested status %q", requested)
+	}
+	if op != nil && op.Responses != nil {
+		responses := *op.Responses
+		if response, ok := responses[strconv.Itoa(code)]; ok {
+			return code, lookupResponse(doc, response), nil
+		}
+		if response, ok := responses[defaultResponseCode]; ok {
+			return code, lookupResponse(doc, response), nil
+		}
+	}
+	declared := make([]string, 0)
+	for _, c := range declaredCodes(op) {
+		declared = append(declared, strconv.Itoa(c))
+	}
+	return 0, nil, fmt.Errorf("status %d is not declared for this operation, declared: [%s]", code, strings.Join(declared, ", "))
+}
+
+func declaredCodes(op *models.Operation) []int {
+	codes := make([]int, 0)
+	if op == nil || op.Responses == nil {
+		return codes
+	}
+	for key := range *op.Responses {
+		if code, err := strconv.Atoi(key); err == nil {
+			codes = append(codes, code)
+		}
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+func lookupResponse(doc *v2.Document, response models.Response) *models.Response {
+	resolved, err := doc.Resolver().Response(&response)
+	if err != nil {
+		logrus.Warn(err)
+		return nil
+	}
+	return resolved
+}
+
+// Example returns the example of the response declared for the media type.
+func Example(response *models.Response, mediaType string) (interface{}, bool) {
+	if response == nil || response.Examples == nil {
+		return nil, false
+	}
+	for key, example := range *response.Examples {
+		if sameMediaType(key, mediaType) {
+			return example, true
+		}
+	}
+	return nil, false
+}
+
+// Encode serializes the body for the media type, following the schema xml hints for xml
+// types. Strings are written verbatim for the other non json types.
+func (g *Generator) Encode(mediaType string, schema *models.Schema, body interface{}) ([]byte, error) {
+	if isXmlMediaType(mediaType) {
+		if text, ok := body.(string); ok && strings.HasPrefix(strings.TrimSpace(text), "<") {
+			return []byte(text), nil
+		}
+		return g.EncodeXml(schema, body)
+	}
+	if text, ok := body.(string); ok && !isJsonMediaType(mediaType) {
+		return []byte(text), nil
+	}
+	return json.Marshal(body)
+}
@@ -0,0 +1,240 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	securityBasic  = "basic"
+	securityApiKey = "apiKey"
+	securityOAuth2 = "oauth2"
+	bearerPrefix   = "bearer "
+	basicPrefix    = "basic "
+)
+
+// TokenInspector returns the scopes granted by an oauth2 access token, false when the token
+// is not accepted.
+type TokenInspector func(token string) ([]string, bool)
+
+type schemeResult int
+
+const (
+	schemeGranted schemeResult = iota
+	schemeMissing
+	schemeForbidden
+)
+
+// InspectJwtClaims is the default TokenInspector: every token is accepted, and the scopes of
+// a jwt are read, unverified, from its "scope" or "scp" claim.
+func InspectJwtClaims(token string) ([]string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return []string{}, true
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return []string{}, true
+	}
+	return jwtScopes(payload), true
+}
+
+func jwtScopes(payload []byte) []string {
+	claims := make(map[string]interface{})
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return []string{}
+	}
+	scopes := make([]string, 0)
+	for _, key := range []string{"scope", "scp"} {
+		switch v := claims[key].(type) {
+		case string:
+			scopes = append(scopes, strings.Fields(v)...)
+		case []interface{}:
+			for _, s := range v {
+				scopes = append(scopes, fmt.Sprint(s))
+			}
+		}
+	}
+	return scopes
+}
+
+// SecurityRequirements returns the requirements of the operation, falling back to the document ones.
+func SecurityRequirements(swagger *models.Swagger, op *models.Operation) []models.SecurityRequirement {
+	if op != nil && op.Security != nil {
+		return *op.Security
+	}
+	if swagger != nil && swagger.Security != nil {
+		return *swagger.Security
+	}
+	return nil
+}
+
+// CreateSecurityValidator enforces the security requirements of the operation: one of the
+// requirements must be met, meeting every scheme it lists. Requests without the credentials
+// get 401 with the challenges of the schemes, the ones lacking oauth2 scopes get 403.
+// It returns nil when the operation has no requirement.
+func CreateSecurityValidator(swagger *models.Swagger, op *models.Operation, inspector TokenInspector) gin.HandlerFunc {
+	requirements := SecurityRequirements(swagger, op)
+	if len(requirements) == 0 {
+		return nil
+	}
+	if inspector == nil {
+		inspector = InspectJwtClaims
+	}
+	definitions := make(map[string]models.Security)
+	if swagger.SecurityDefinitions != nil {
+		definitions = *swagger.SecurityDefinitions
+	}
+	for _, requirement := range requirements {
+		for name := range requirement {
+			if _, ok := definitions[name]; !ok {
+				logrus.Warnf("%s: security definition %s is not declared", operationName(op), name)
+			}
+		}
+	}
+	challenges := securityChallenges(swagger, requirements, definitions)
+	return func(ctx *gin.Context) {
+		forbidden := false
+		for _, requirement := range requirements {
+			result := checkRequirement(ctx.Request, requirement, definitions, inspector)
+			if result == schemeGranted {
+				return
+			}
+			forbidden = forbidden || result == schemeForbidden
+		}
+		if forbidden {
+			for _, challenge := range challenges {
+				if strings.HasPrefix(challenge, "Bearer") {
+					ctx.Writer.Header().Add("WWW-Authenticate", challenge+`, error="insufficient_scope"`)
+				}
+			}
+			AbortWithError(ctx, http.StatusForbidden, "insufficient scopes")
+			return
+		}
+		for _, challenge := range challenges {
+			ctx.Writer.Header().Add("WWW-Authenticate", challenge)
+		}
+		AbortWithError(ctx, http.StatusUnauthorized, "missing or invalid credentials")
+	}
+}
+
+// checkRequirement returns the worst result of the schemes of the requirement.
+func checkRequirement(req *http.Request, requirement models.SecurityRequirement, definitions map[string]models.Security, inspector TokenInspector) schemeResult {
+	result := schemeGranted
+	for name, scopes := range requirement {
+		definition, ok := definitions[name]
+		if !ok {
+			return schemeMissing
+		}
+		switch checkScheme(req, definition, scopes, inspector) {
+		case schemeMissing:
+			return schemeMissing
+		case schemeForbidden:
+			result = schemeForbidden
+		}
+	}
+	return result
+}
+
+func checkScheme(req *http.Request, definition models.Security, scopes []string, inspector TokenInspector) schemeResult {
+	authorization := req.Header.Get("Authorization")
+	switch securityType(definition) {
+	case securityBasic:
+		if !strings.HasPrefix(strings.ToLower(authorization), basicPrefix) {
+			return schemeMissing
+		}
+		credentials, err := base64.StdEncoding.DecodeString(strings.TrimSpace(authorization[len(basicPrefix):]))
+		if err != nil || !strings.Contains(string(credentials), ":") {
+			return schemeMissing
+		}
+		return schemeGranted
+	case securityApiKey:
+		if definition.Name == nil {
+			return schemeMissing
+		}
+		value := req.Header.Get(*definition.Name)
+		if definition.In != nil && *definition.In == "query" {
+			value = req.URL.Query().Get(*definition.Name)
+		}
+		if len(value) == 0 {
+			return schemeMissing
+		}
+		return schemeGranted
+	case securityOAuth2:
+		if !strings.HasPrefix(strings.ToLower(authorization), bearerPrefix) {
+			return schemeMissing
+		}
+		granted, ok := inspector(strings.TrimSpace(authorization[len(bearerPrefix):]))
+		if !ok {
+			return schemeMissing
+		}
+		for _, scope := range scopes {
+			if !contains(granted, scope) {
+				return schemeForbidden
+			}
+		}
+		return schemeGranted
+	}
+	return schemeMissing
+}
+
+// securityChallenges builds a WWW-Authenticate challenge for every scheme of the requirements.
+func securityChallenges(swagger *models.Swagger, requirements []models.SecurityRequirement, definitions map[string]models.Security) []string {
+	realm := "mock"
+	if swagger.Info != nil && swagger.Info.Title != nil {
+		realm = *swagger.Info.Title
+	}
+	challenges := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, requirement := range requirements {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			definition, ok := definitions[name]
+			if !ok {
+				continue
+			}
+			challenge := ""
+			switch securityType(definition) {
+			case securityBasic:
+				challenge = fmt.Sprintf("Basic realm=%q", realm)
+			case securityApiKey:
+				in, key := "header", ""
+				if definition.In != nil {
+					in = *definition.In
+				}
+				if definition.Name != nil {
+					key = *definition.Name
+				}
+				challenge = fmt.Sprintf("ApiKey realm=%q, name=%q, in=%q", realm, key, in)
+			case securityOAuth2:
+				challenge = fmt.Sprintf("Bearer realm=%q", realm)
+				if scopes := requirement[name]; len(scopes) > 0 {
+					challenge += fmt.Sprintf(", scope=%q", strings.Join(scopes, " "))
+				}
+			}
+			if len(challenge) > 0 && !seen[challenge] {
+				seen[challenge] = true
+				challenges = append(challenges, challenge)
+			}
+		}
+	}
+	return challenges
+}
+
+func securityType(definition models.Security) string {
+	if definition.Type != nil {
+		return *definition.Type
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package common
+
+import (
+	"encoding/base64"
+	"github.com/gin-gonic/gin"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const securitySpec = `swagger: "2.0"
+info: {title: pets, version: "1"}
+securityDefinitions:
+  key: {type: apiKey, in: header, name: X-Key}
+  basic: {type: basic}
+  oauth: {type: oauth2, flow: implicit, authorizationUrl: "https://example.com/authorize", scopes: {read: read, write: write}}
+paths:
+  /pets:
+    post:
+      security:
+        - {key: [], basic: []}
+        - {oauth: [write]}
+      responses:
+        "200": {description: ok}
+`
+
+func bearer(scope string) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"scope": "` + scope + `"}`))
+	return "Bearer e30." + payload + ".signature"
+}
+
+func TestSecurityValidator(t *testing.T) {
+	doc, err := v2.LoadBytes([]byte(securitySpec), "spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Valid() {
+		t.Fatalf("unexpected errors %v", doc.Errors)
+	}
+	op := (*doc.Swagger.Paths)["/pets"].Post
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.POST("/pets", CreateSecurityValidator(&doc.Swagger, op, nil), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+	tests := []struct {
+		name          string
+		headers       map[string]string
+		want          int
+		wantChallenge string
+	}{
+		{name: "no credentials", want: http.StatusUnauthorized, wantChallenge: `ApiKey realm="pets", name="X-Key", in="header"`},
+		{name: "first requirement partly met", headers: map[string]string{"X-Key": "k"}, want: http.StatusUnauthorized, wantChallenge: `Basic realm="pets"`},
+		{name: "first requirement met", headers: map[string]string{"X-Key": "k", "Authorization": basic}, want: http.StatusOK},
+		{name: "malformed basic credentials", headers: map[string]string{"X-Key": "k", "Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte("user"))}, want: http.StatusUnauthorized},
+		{name: "second requirement met", headers: map[string]string{"Authorization": bearer("read write")}, want: http.StatusOK},
+		{name: "second requirement lacking a scope", headers: map[string]string{"Authorization": bearer("read")}, want: http.StatusForbidden, wantChallenge: `Bearer realm="pets", scope="write", error="insufficient_scope"`},
+		{name: "api key with a token lacking a scope", headers: map[string]string{"X-Key": "k", "Authorization": bearer("read")}, want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/pets", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			challenges := strings.Join(w.Header().Values("WWW-Authenticate"), "\n")
+			if len(tt.wantChallenge) > 0 && !strings.Contains(challenges, tt.wantChallenge) {
+				t.Errorf("WWW-Authenticate = %q, want %q", challenges, tt.wantChallenge)
+			}
+		})
+	}
+}
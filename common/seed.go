@@ -0,0 +1,48 @@
+package common
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SeedHeader sets the seed of the values generated for a request, e.g. "X-Mock-Seed: 42".
+// The seed used is echoed in the response header with the same name.
+const SeedHeader = "X-Mock-Seed"
+
+// RequestSeed derives the seed of the values generated for a request from the global seed,
+// the operation, the path parameters and the query string, so the same request always gets
+// the same values. A SeedHeader, an integer or any other text, overrides it.
+func RequestSeed(ctx *gin.Context, op *models.Operation, seed int64) int64 {
+	if header := strings.TrimSpace(ctx.GetHeader(SeedHeader)); len(header) > 0 {
+		if s, err := strconv.ParseInt(header, 10, 64); err == nil {
+			return s
+		}
+		return hashSeed(header)
+	}
+	operation := ctx.Request.Method + " " + ctx.FullPath()
+	if op.OperationId != nil {
+		operation = *op.OperationId
+	}
+	params := append(gin.Params{}, ctx.Params...)
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Key < params[j].Key
+	})
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\n%s\n", seed, operation)
+	for _, p := range params {
+		fmt.Fprintf(&b, "%s=%s\n", p.Key, p.Value)
+	}
+	b.WriteString(ctx.Request.URL.Query().Encode())
+	return hashSeed(b.String())
+}
+
+func hashSeed(value string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(value))
+	return int64(h.Sum64())
+}
@@ -2,7 +2,12 @@ package common
 
 import (
 	"encoding/json"
+	"fmt"
+	"math/big"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 func Pointer[T any](i T) *T {
@@ -17,6 +22,67 @@ func ToString[T any](i T) (string, error) {
 	return string(data), nil
 }
 
+// stringValue formats a generated primitive value, without exponent for numbers.
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(value)
+}
+
+// sameValue compares json values, numbers by their exact value whatever their go type.
+func sameValue(a interface{}, b interface{}) bool {
+	if x, ok := ratValue(a); ok {
+		y, ok := ratValue(b)
+		return ok && x.Cmp(y) == 0
+	}
+	if _, ok := ratValue(b); ok {
+		return false
+	}
+	x, errA := ToString(a)
+	y, errB := ToString(b)
+	return errA == nil && errB == nil && x == y
+}
+
+func ratValue(value interface{}) (*big.Rat, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(string(v))
+	case int64:
+		return new(big.Rat).SetInt64(v), true
+	case int:
+		return new(big.Rat).SetInt64(int64(v)), true
+	case float64:
+		return new(big.Rat).SetString(strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	return nil, false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// splitHeader splits a comma separated header value, dropping empty entries.
+func splitHeader(value string) []string {
+	values := make([]string, 0)
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 // Regex
 const (
 	bracketRegex    = `{(.*?)}`
@@ -0,0 +1,354 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/sirupsen/logrus"
+	"github.com/xeipuuv/gojsonschema"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Violation describes a request value breaking a rule of its definition.
+type Violation struct {
+	In      string `json:"in,omitempty"`
+	Name    string `json:"name"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// primitive gathers the fields describing a non body parameter, a header or an items object.
+type primitive struct {
+	models.TypeStruct
+	models.Restrictions
+	Items            *models.PrimitivesItems
+	CollectionFormat *string
+}
+
+func parameterPrimitive(p *models.Parameter) primitive {
+	return primitive{TypeStruct: p.TypeStruct, Restrictions: p.Restrictions, Items: p.Items, CollectionFormat: p.CollectionFormat}
+}
+
+func itemsPrimitive(items *models.PrimitivesItems) primitive {
+	if items == nil {
+		return primitive{}
+	}
+	return primitive{TypeStruct: items.TypeStruct, Restrictions: items.Restrictions, Items: items.Items, CollectionFormat: items.CollectionFormat}
+}
+
+var patterns sync.Map
+
+// CreateParameterValidator checks the path, query and header parameters of the requests,
+// aborting with 400 and the list of violations. It returns nil when the mode is ValidationOff.
+func CreateParameterValidator(op *models.Operation, mode ValidationMode) gin.HandlerFunc {
+	if mode == ValidationOff {
+		return nil
+	}
+	params := make([]models.Parameter, 0)
+	params = append(params, op.GetPathParameters()...)
+	params = append(params, op.GetQueryParameters()...)
+	params = append(params, op.GetHeaderParameters()...)
+	return func(ctx *gin.Context) {
+		violations := make([]Violation, 0)
+		for _, p := range params {
+			p := p
+			values, found := parameterValues(ctx, &p)
+			violations = append(violations, checkParameter(&p, values, found)...)
+		}
+		if len(violations) > 0 {
+			reportViolations(ctx, mode, violations)
+		}
+	}
+}
+
+// reportViolations aborts with the violations, or only logs them in lenient mode.
+func reportViolations(ctx *gin.Context, mode ValidationMode, violations []Violation) {
+	if mode != ValidationLenient {
+		AbortWithViolations(ctx, violations)
+		return
+	}
+	for _, v := range violations {
+		logrus.Warnf("%s %s: %s %s breaks %s: %s", ctx.Request.Method, ctx.Request.URL.Path, v.In, v.Name, v.Rule, v.Message)
+	}
+}
+
+func parameterValues(ctx *gin.Context, p *models.Parameter) ([]string, bool) {
+	name := ""
+	if p.Name != nil {
+		name = *p.Name
+	}
+	switch *p.In {
+	case "path":
+		value := ctx.Param(name)
+		return []string{value}, len(value) > 0
+	case "query":
+		values, ok := ctx.Request.URL.Query()[name]
+		return values, ok
+	case "header":
+		values := ctx.Request.Header.Values(name)
+		return values, len(values) > 0
+	}
+	return nil, false
+}
+
+// checkParameter validates the raw values received for a parameter.
+func checkParameter(p *models.Parameter, values []string, found bool) []Violation {
+	in, name := "", ""
+	if p.In != nil {
+		in = *p.In
+	}
+	if p.Name != nil {
+		name = *p.Name
+	}
+	if !found {
+		if (p.Required != nil && *p.Required) || in == "path" {
+			return []Violation{{In: in, Name: name, Rule: "required", Message: "is required"}}
+		}
+		return nil
+	}
+	if p.AllowEmptyValue != nil && *p.AllowEmptyValue && len(values) == 1 && len(values[0]) == 0 {
+		return nil
+	}
+	violations := make([]Violation, 0)
+	for _, v := range checkValues(name, values, parameterPrimitive(p)) {
+		v.In = in
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// checkValues coerces raw values to the primitive type and checks its restrictions.
+func checkValues(name string, values []string, p primitive) []Violation {
+	if p.Type != nil && *p.Type == "array" {
+		elements, ok := splitCollection(values, p.CollectionFormat)
+		if !ok {
+			format := collectionCsv
+			if p.CollectionFormat != nil {
+				format = *p.CollectionFormat
+			}
+			return []Violation{{Name: name, Rule: "collectionFormat", Message: fmt.Sprintf("expects a single %s value", format)}}
+		}
+		violations := checkArray(name, len(elements), uniqueStrings(elements), p.Restrictions)
+		for i, element := range elements {
+			violations = append(violations, checkValues(fmt.Sprintf("%s[%d]", name, i), []string{element}, itemsPrimitive(p.Items))...)
+		}
+		return violations
+	}
+	raw := ""
+	if len(values) > 0 {
+		raw = values[0]
+	}
+	value, violation := coerce(raw, p.TypeStruct)
+	if violation != nil {
+		violation.Name = name
+		return []Violation{*violation}
+	}
+	return checkRestrictions(name, raw, value, p.Restrictions)
+}
+
+// coerce converts a raw value to the type and format.
+func coerce(raw string, t models.TypeStruct) (interface{}, *Violation) {
+	typ, format := "", ""
+	if t.Type != nil {
+		typ = *t.Type
+	}
+	if t.Format != nil {
+		format = *t.Format
+	}
+	invalid := func(expected string) (interface{}, *Violation) {
+		if len(format) > 0 {
+			expected = format
+		}
+		return nil, &Violation{Rule: "type", Message: fmt.Sprintf("%q is not a valid %s", raw, expected)}
+	}
+	switch typ {
+	case "integer":
+		bits := 64
+		if format == "int32" {
+			bits = 32
+		}
+		i, err := strconv.ParseInt(raw, 10, bits)
+		if err != nil {
+			return invalid("integer")
+		}
+		return i, nil
+	case "number":
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return invalid("number")
+		}
+		return f, nil
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return invalid("boolean")
+		}
+		return b, nil
+	}
+	var err error
+	switch format {
+	case "date":
+		_, err = time.Parse("2006-01-02", raw)
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, raw)
+	case "byte":
+		_, err = base64.StdEncoding.DecodeString(raw)
+	default:
+		// the formats checked in bodies: email, uuid, uri, hostname, ipv4, ipv6...
+		if !gojsonschema.FormatCheckers.IsFormat(format, raw) {
+			return invalid(typ)
+		}
+	}
+	if err != nil {
+		return invalid(typ)
+	}
+	return raw, nil
+}
+
+func checkRestrictions(name string, raw string, value interface{}, r models.Restrictions) []Violation {
+	violations := make([]Violation, 0)
+	add := func(rule string, format string, args ...interface{}) {
+		violations = append(violations, Violation{Name: name, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+	if r.Enum != nil && len(*r.Enum) > 0 && !enumContains(*r.Enum, exactValue(raw, value)) {
+		values := make([]string, 0, len(*r.Enum))
+		for _, e := range *r.Enum {
+			values = append(values, stringValue(e))
+		}
+		add("enum", "must be one of [%s]", strings.Join(values, ", "))
+	}
+	switch v := value.(type) {
+	case int64:
+		violations = append(violations, checkNumber(name, exactNumber(raw, new(big.Rat).SetInt64(v)), r)...)
+	case float64:
+		violations = append(violations, checkNumber(name, exactNumber(raw, new(big.Rat).SetFloat64(v)), r)...)
+	case string:
+		length := utf8.RuneCountInString(v)
+		if r.MaxLength != nil && length > *r.MaxLength {
+			add("maxLength", "length must be <= %d", *r.MaxLength)
+		}
+		if r.MinLength != nil && length < *r.MinLength {
+			add("minLength", "length must be >= %d", *r.MinLength)
+		}
+		if r.Pattern != nil {
+			if re := compilePattern(*r.Pattern); re != nil && !re.MatchString(v) {
+				add("pattern", "must match %s", *r.Pattern)
+			}
+		}
+	}
+	return violations
+}
+
+func checkNumber(name string, v *big.Rat, r models.Restrictions) []Violation {
+	violations := make([]Violation, 0)
+	add := func(rule string, format string, args ...interface{}) {
+		violations = append(violations, Violation{Name: name, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+	if r.Maximum != nil {
+		c := v.Cmp(r.Maximum.Rat())
+		if r.ExclusiveMaximum != nil && *r.ExclusiveMaximum {
+			if c >= 0 {
+				add("exclusiveMaximum", "must be < %s", r.Maximum)
+			}
+		} else if c > 0 {
+			add("maximum", "must be <= %s", r.Maximum)
+		}
+	}
+	if r.Minimum != nil {
+		c := v.Cmp(r.Minimum.Rat())
+		if r.ExclusiveMinimum != nil && *r.ExclusiveMinimum {
+			if c <= 0 {
+				add("exclusiveMinimum", "must be > %s", r.Minimum)
+			}
+		} else if c < 0 {
+			add("minimum", "must be >= %s", r.Minimum)
+		}
+	}
+	if r.MultipleOf != nil && r.MultipleOf.Rat().Sign() != 0 {
+		if !new(big.Rat).Quo(v, r.MultipleOf.Rat()).IsInt() {
+			add("multipleOf", "must be a multiple of %s", r.MultipleOf)
+		}
+	}
+	return violations
+}
+
+// exactNumber parses a raw number without the rounding of float64, "0.1" being exactly 1/10,
+// falling back to the coerced value.
+func exactNumber(raw string, coerced *big.Rat) *big.Rat {
+	if v, ok := new(big.Rat).SetString(raw); ok {
+		return v
+	}
+	return coerced
+}
+
+func checkArray(name string, length int, unique bool, r models.Restrictions) []Violation {
+	violations := make([]Violation, 0)
+	if r.MaxItems != nil && length > *r.MaxItems {
+		violations = append(violations, Violation{Name: name, Rule: "maxItems", Message: fmt.Sprintf("must have <= %d items", *r.MaxItems)})
+	}
+	if r.MinItems != nil && length < *r.MinItems {
+		violations = append(violations, Violation{Name: name, Rule: "minItems", Message: fmt.Sprintf("must have >= %d items", *r.MinItems)})
+	}
+	if r.UniqueItems != nil && *r.UniqueItems && !unique {
+		violations = append(violations, Violation{Name: name, Rule: "uniqueItems", Message: "items must be unique"})
+	}
+	return violations
+}
+
+// enumContains tells whether the value is one of the enum values, numbers being compared exactly.
+func enumContains(enum models.Enum, value interface{}) bool {
+	for _, e := range enum {
+		if sameValue(e, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// exactValue keeps the text of a coerced number, which float64 may round.
+func exactValue(raw string, value interface{}) interface{} {
+	switch value.(type) {
+	case int64, float64:
+		if _, ok := new(big.Rat).SetString(raw); ok {
+			return json.Number(raw)
+		}
+	}
+	return value
+}
+
+// compilePattern caches the compiled patterns, ECMA-262 ones being approximated, logging the
+// ones go cannot compile.
+func compilePattern(pattern string) *regexp.Regexp {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	var re *regexp.Regexp
+	if approximation, _, ok := v2.ApproximatePattern(pattern); ok {
+		re = regexp.MustCompile(approximation)
+	} else {
+		logrus.Warnf("ignoring pattern %s: not a supported regular expression", pattern)
+	}
+	patterns.Store(pattern, re)
+	return re
+}
+
+func uniqueStrings(values []string) bool {
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if seen[v] {
+			return false
+		}
+		seen[v] = true
+	}
+	return true
+}
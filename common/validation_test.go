@@ -0,0 +1,116 @@
+package common
+
+import (
+	"encoding/json"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func typeStruct(typ string, format string) models.TypeStruct {
+	t := models.TypeStruct{Type: &typ}
+	if len(format) > 0 {
+		t.Format = &format
+	}
+	return t
+}
+
+func TestCoerce(t *testing.T) {
+	tests := []struct {
+		typ, format string
+		raw         string
+		want        interface{}
+		wantErr     bool
+	}{
+		{typ: "integer", raw: "42", want: int64(42)},
+		{typ: "integer", format: "int32", raw: "4294967296", wantErr: true},
+		{typ: "number", raw: "1.5", want: 1.5},
+		{typ: "number", raw: "NaN", wantErr: true},
+		{typ: "boolean", raw: "true", want: true},
+		{typ: "string", raw: "anything", want: "anything"},
+		{typ: "string", format: "date", raw: "2024-02-30", wantErr: true},
+		{typ: "string", format: "date-time", raw: "2024-02-01T10:00:00Z", want: "2024-02-01T10:00:00Z"},
+		{typ: "string", format: "byte", raw: "not base64!", wantErr: true},
+		{typ: "string", format: "email", raw: "jane@example.com", want: "jane@example.com"},
+		{typ: "string", format: "email", raw: "jane", wantErr: true},
+		{typ: "string", format: "uuid", raw: "123e4567-e89b-12d3-a456-426614174000", want: "123e4567-e89b-12d3-a456-426614174000"},
+		{typ: "string", format: "uuid", raw: "123e4567", wantErr: true},
+		{typ: "string", format: "uri", raw: "https://example.com/a?b=c", want: "https://example.com/a?b=c"},
+		{typ: "string", format: "uri", raw: "/relative/path", wantErr: true},
+		{typ: "string", format: "hostname", raw: "api.example.com", want: "api.example.com"},
+		{typ: "string", format: "hostname", raw: "bad_host!", wantErr: true},
+		{typ: "string", format: "ipv4", raw: "192.0.2.1", want: "192.0.2.1"},
+		{typ: "string", format: "ipv4", raw: "2001:db8::1", wantErr: true},
+		{typ: "string", format: "ipv6", raw: "2001:db8::1", want: "2001:db8::1"},
+		{typ: "string", format: "ipv6", raw: "192.0.2.1", wantErr: true},
+		{typ: "string", format: "custom", raw: "free text", want: "free text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ+" "+tt.format+" "+tt.raw, func(t *testing.T) {
+			got, violation := coerce(tt.raw, typeStruct(tt.typ, tt.format))
+			if (violation != nil) != tt.wantErr {
+				t.Fatalf("coerce(%q) violation = %v, wantErr %v", tt.raw, violation, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("coerce(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratedFormatsAreValid(t *testing.T) {
+	for _, format := range []string{"date", "date-time", "byte", "email", "uuid", "uri", "hostname", "ipv4", "ipv6"} {
+		f := format
+		if _, violation := coerce(defaultString(&f), typeStruct("string", format)); violation != nil {
+			t.Errorf("default %s: %s", format, violation.Message)
+		}
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 50; i++ {
+			value := fakeString(rnd, "value", format)
+			if _, violation := coerce(value, typeStruct("string", format)); violation != nil {
+				t.Errorf("realistic %s: %s", format, violation.Message)
+			}
+		}
+	}
+}
+
+func TestEnumContains(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  string
+		enum models.Enum
+		raw  string
+		want bool
+	}{
+		{name: "integer", typ: "integer", enum: models.Enum{json.Number("1"), json.Number("2")}, raw: "2", want: true},
+		{name: "integer not listed", typ: "integer", enum: models.Enum{json.Number("1"), json.Number("2")}, raw: "3"},
+		{name: "large integer", typ: "integer", enum: models.Enum{json.Number("9007199254740993")}, raw: "9007199254740992"},
+		{name: "number written differently", typ: "number", enum: models.Enum{json.Number("0.5"), json.Number("1e-3")}, raw: "0.0010", want: true},
+		{name: "boolean", typ: "boolean", enum: models.Enum{true}, raw: "true", want: true},
+		{name: "boolean not listed", typ: "boolean", enum: models.Enum{true}, raw: "false"},
+		{name: "string", typ: "string", enum: models.Enum{"red", "1"}, raw: "1", want: true},
+		{name: "string is not a number", typ: "string", enum: models.Enum{json.Number("1")}, raw: "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, violation := coerce(tt.raw, typeStruct(tt.typ, ""))
+			if violation != nil {
+				t.Fatal(violation.Message)
+			}
+			violations := checkRestrictions("p", tt.raw, value, models.Restrictions{Enum: &tt.enum})
+			if got := len(violations) == 0; got != tt.want {
+				t.Errorf("%s in %v = %v, want %v (%v)", tt.raw, tt.enum, got, tt.want, violations)
+			}
+		})
+	}
+}
+
+func TestMergeRestrictionsEnum(t *testing.T) {
+	into := models.Restrictions{Enum: &models.Enum{json.Number("1"), json.Number("2"), json.Number("3")}}
+	from := models.Restrictions{Enum: &models.Enum{int64(3), 2.0, "1"}}
+	mergeRestrictions(&into, &from)
+	if want := (models.Enum{json.Number("2"), json.Number("3")}); !reflect.DeepEqual(*into.Enum, want) {
+		t.Errorf("merged enum = %#v, want %#v", *into.Enum, want)
+	}
+}
@@ -0,0 +1,217 @@
+package common
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"sort"
+	"strings"
+)
+
+const defaultXmlRoot = "root"
+
+// xmlEncoder writes a generated value as xml, following the xml hints of its schema.
+type xmlEncoder struct {
+	generator *Generator
+	encoder   *xml.Encoder
+}
+
+// EncodeXml renders a value generated from the schema as xml. Without a schema the
+// value is written using its map keys as element names.
+func (g *Generator) EncodeXml(schema *models.Schema, value interface{}) ([]byte, error) {
+	buffer := bytes.NewBufferString(xml.Header)
+	e := &xmlEncoder{generator: g, encoder: xml.NewEncoder(buffer)}
+	name := defaultXmlRoot
+	if schema != nil && schema.Ref != nil {
+		name = schema.GetRefName()
+	}
+	own, resolved := e.resolve(schema)
+	if err := e.encode(e.elementName(name, xmlHints(own, resolved)), own, resolved, value); err != nil {
+		return nil, err
+	}
+	if err := e.encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// resolve returns the xml hints declared next to the schema and the resolved schema.
+func (e *xmlEncoder) resolve(schema *models.Schema) (*models.Xml, *models.Schema) {
+	if schema == nil {
+		return nil, nil
+	}
+	own := schema.Xml
+	resolved := schema
+	if schema.Ref != nil {
+		r, err := e.generator.resolver.Schema(schema)
+		if err != nil {
+			return own, nil
+		}
+		resolved = r
+	}
+	return own, e.generator.mergeAllOf(resolved, make(map[string]bool))
+}
+
+func (e *xmlEncoder) encode(name string, own *models.Xml, schema *models.Schema, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	start := e.startElement(name, own, schema)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return e.encodeObject(start, schema, v)
+	case []interface{}:
+		return e.encodeArray(start, name, schema, v)
+	}
+	if err := e.encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := e.encoder.EncodeToken(xml.CharData(stringValue(value))); err != nil {
+		return err
+	}
+	return e.encoder.EncodeToken(start.End())
+}
+
+func (e *xmlEncoder) encodeObject(start xml.StartElement, schema *models.Schema, obj map[string]interface{}) error {
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	children := make([]string, 0, len(names))
+	for _, name := range names {
+		own, prop := e.property(schema, name)
+		if isXmlAttribute(own, prop) {
+			if _, nested := obj[name].(map[string]interface{}); !nested && obj[name] != nil {
+				start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: e.elementName(name, own)}, Value: stringValue(obj[name])})
+				continue
+			}
+		}
+		children = append(children, name)
+	}
+	if err := e.encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, name := range children {
+		own, prop := e.property(schema, name)
+		if arr, ok := obj[name].([]interface{}); ok && !isXmlWrapped(own, prop) {
+			// unwrapped arrays repeat their items in place of the property
+			if err := e.encodeItems(name, prop, arr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := e.encode(e.elementName(name, own), own, prop, obj[name]); err != nil {
+			return err
+		}
+	}
+	return e.encoder.EncodeToken(start.End())
+}
+
+func (e *xmlEncoder) encodeArray(start xml.StartElement, name string, schema *models.Schema, arr []interface{}) error {
+	if err := e.encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+	if err := e.encodeItems(name, schema, arr); err != nil {
+		return err
+	}
+	return e.encoder.EncodeToken(start.End())
+}
+
+// encodeItems writes the array items, named after the items xml name or the array name.
+func (e *xmlEncoder) encodeItems(name string, schema *models.Schema, arr []interface{}) error {
+	var list []models.Schema
+	var additional *models.Schema
+	tuple := schema != nil && schema.HasTupleItems()
+	if schema != nil {
+		list = schema.GetItems()
+		additional = schema.GetAdditionalItems()
+	}
+	for i, item := range arr {
+		var itemsOwn *models.Xml
+		var items *models.Schema
+		switch {
+		case len(list) == 0:
+		case tuple && i >= len(list):
+			// the items following tuple items are additionalItems ones
+			if additional != nil {
+				itemsOwn, items = e.resolve(additional)
+			}
+		case tuple:
+			itemsOwn, items = e.resolve(&list[i])
+		default:
+			itemsOwn, items = e.resolve(&list[0])
+		}
+		if err := e.encode(e.elementName(name, xmlHints(itemsOwn, items)), itemsOwn, items, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *xmlEncoder) property(schema *models.Schema, name string) (*models.Xml, *models.Schema) {
+	if schema == nil || schema.Properties == nil {
+		return nil, nil
+	}
+	prop, ok := (*schema.Properties)[name]
+	if !ok {
+		return nil, nil
+	}
+	return e.resolve(&prop)
+}
+
+func (e *xmlEncoder) startElement(name string, own *models.Xml, schema *models.Schema) xml.StartElement {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	hints := xmlHints(own, schema)
+	if hints != nil && hints.Namespace != nil && len(*hints.Namespace) > 0 {
+		attr := "xmlns"
+		if hints.Prefix != nil && len(*hints.Prefix) > 0 {
+			attr = "xmlns:" + *hints.Prefix
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: attr}, Value: *hints.Namespace})
+	}
+	return start
+}
+
+// elementName applies the xml name and prefix hints to the default name.
+func (e *xmlEncoder) elementName(name string, hints *models.Xml) string {
+	if hints == nil {
+		return name
+	}
+	if hints.Name != nil && len(*hints.Name) > 0 {
+		name = *hints.Name
+	}
+	if hints.Prefix != nil && len(*hints.Prefix) > 0 {
+		name = *hints.Prefix + ":" + name
+	}
+	return name
+}
+
+// xmlHints returns the hints declared next to a reference, or the ones of the schema.
+func xmlHints(own *models.Xml, schema *models.Schema) *models.Xml {
+	if own != nil {
+		return own
+	}
+	if schema != nil {
+		return schema.Xml
+	}
+	return nil
+}
+
+func isXmlAttribute(own *models.Xml, schema *models.Schema) bool {
+	hints := xmlHints(own, schema)
+	return hints != nil && hints.Attribute != nil && *hints.Attribute
+}
+
+func isXmlWrapped(own *models.Xml, schema *models.Schema) bool {
+	hints := xmlHints(own, schema)
+	return hints != nil && hints.Wrapped != nil && *hints.Wrapped
+}
+
+func isXmlMediaType(mediaType string) bool {
+	t, sub := splitMediaType(mediaType)
+	return (t == "application" || t == "text") && (sub == "xml" || strings.HasSuffix(sub, "+xml"))
+}
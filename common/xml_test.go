@@ -0,0 +1,147 @@
+package common
+
+import (
+	"encoding/xml"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"strings"
+	"testing"
+)
+
+const xmlSpec = `swagger: "2.0"
+info: {title: xml, version: "1"}
+paths: {}
+definitions:
+  Pet:
+    type: object
+    properties:
+      id: {type: integer, xml: {attribute: true}}
+      code: {type: string, xml: {attribute: true, name: ref, prefix: p}}
+      name: {type: string, xml: {name: title}}
+  Renamed:
+    type: object
+    xml: {name: animal, prefix: a, namespace: "https://example.com/animal"}
+    properties:
+      name: {type: string}
+  Default:
+    type: object
+    xml: {namespace: "https://example.com/default"}
+    properties:
+      name: {type: string}
+  Wrapped:
+    type: object
+    properties:
+      tags: {type: array, items: {type: string}, xml: {wrapped: true}}
+  Unwrapped:
+    type: object
+    properties:
+      tags: {type: array, items: {type: string}}
+  RenamedItems:
+    type: object
+    properties:
+      tags: {type: array, xml: {name: labels, wrapped: true}, items: {type: string, xml: {name: label, prefix: t, namespace: "https://example.com/tag"}}}
+  Tags: {type: array, items: {type: string}}
+  Items: {type: array, xml: {name: list}, items: {$ref: "#/definitions/Item"}}
+  Item: {type: string, xml: {name: entry}}
+  Point: {type: array, items: [{type: integer, xml: {name: x}}, {type: integer, xml: {name: "y"}}], additionalItems: {type: string, xml: {name: label}}}
+`
+
+func TestEncodeXml(t *testing.T) {
+	doc, err := v2.LoadBytes([]byte(xmlSpec), "spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGenerator(doc)
+	tests := []struct {
+		name       string
+		definition string
+		value      string
+		want       string
+	}{
+		{
+			name:       "attributes",
+			definition: "Pet",
+			value:      `{"id": 7, "code": "x1", "name": "Rex"}`,
+			want:       `<Pet p:ref="x1" id="7"><title>Rex</title></Pet>`,
+		},
+		{
+			name:       "root name, prefix and namespace",
+			definition: "Renamed",
+			value:      `{"name": "Rex"}`,
+			want:       `<a:animal xmlns:a="https://example.com/animal"><name>Rex</name></a:animal>`,
+		},
+		{
+			name:       "default namespace",
+			definition: "Default",
+			value:      `{"name": "Rex"}`,
+			want:       `<Default xmlns="https://example.com/default"><name>Rex</name></Default>`,
+		},
+		{
+			name:       "wrapped array",
+			definition: "Wrapped",
+			value:      `{"tags": ["a", "b"]}`,
+			want:       `<Wrapped><tags><tags>a</tags><tags>b</tags></tags></Wrapped>`,
+		},
+		{
+			name:       "unwrapped array",
+			definition: "Unwrapped",
+			value:      `{"tags": ["a", "b"]}`,
+			want:       `<Unwrapped><tags>a</tags><tags>b</tags></Unwrapped>`,
+		},
+		{
+			name:       "item name, prefix and namespace",
+			definition: "RenamedItems",
+			value:      `{"tags": ["a", "b"]}`,
+			want: `<RenamedItems><labels><t:label xmlns:t="https://example.com/tag">a</t:label>` +
+				`<t:label xmlns:t="https://example.com/tag">b</t:label></labels></RenamedItems>`,
+		},
+		{
+			name:       "root array",
+			definition: "Tags",
+			value:      `["a", "b"]`,
+			want:       `<Tags><Tags>a</Tags><Tags>b</Tags></Tags>`,
+		},
+		{
+			name:       "referenced items",
+			definition: "Items",
+			value:      `["a", "b"]`,
+			want:       `<list><entry>a</entry><entry>b</entry></list>`,
+		},
+		{
+			name:       "tuple items",
+			definition: "Point",
+			value:      `[1, 2, "origin", "centre"]`,
+			want:       `<Point><x>1</x><y>2</y><label>origin</label><label>centre</label></Point>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := v2.DecodeJson([]byte(tt.value))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ref := "#/definitions/" + tt.definition
+			data, err := g.EncodeXml(&models.Schema{Ref: &ref}, value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimPrefix(string(data), xml.Header); got != tt.want {
+				t.Errorf("EncodeXml(%s) = %s, want %s", tt.definition, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeXmlWithoutSchema(t *testing.T) {
+	value, err := v2.DecodeJson([]byte(`{"name": "Rex", "tags": ["a"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := (&Generator{}).EncodeXml(nil, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimPrefix(string(data), xml.Header), `<root><name>Rex</name><tags>a</tags></root>`; got != want {
+		t.Errorf("EncodeXml() = %s, want %s", got, want)
+	}
+}
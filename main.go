@@ -1,46 +1,39 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"github.com/heimbogdan/go-swagger-mock/common"
-	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
-	v2m "github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
-	"github.com/xeipuuv/gojsonschema"
+	"github.com/sirupsen/logrus"
+	"os"
 )
 
+const usage = `Usage: go-swagger-mock <command> [flags]
+
+Commands:
+  serve    start a mock server for a swagger 2.0 spec
+  validate check a swagger 2.0 spec for schema and semantic issues
+  help     show this message
+
+Run 'go-swagger-mock <command> -h' for the command flags.
+`
+
 func main() {
-	petStore := gojsonschema.NewReferenceLoader("https://petstore.swagger.io/v2/swagger.json")
-	validate, err := v2.SwaggerV2Schema.Validate(petStore)
-	if err != nil {
-		panic(err.Error())
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
 	}
-	if validate.Valid() {
-		fmt.Println("valid")
-	} else {
-		fmt.Printf("The document is not valid. see errors :\n")
-		for _, desc := range validate.Errors() {
-			fmt.Printf("- %s\n", desc)
-		}
+	var err error
+	switch os.Args[1] {
+	case "serve":
+		err = serve(os.Args[2:])
+	case "validate":
+		err = validate(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
 	}
-	petJson, _ := petStore.LoadJSON()
-	swagg := v2m.Swagger{}
-	data, _ := common.ToString(petJson)
-	err = json.Unmarshal([]byte(data), &swagg)
 	if err != nil {
-		fmt.Println(err)
-	}
-
-	//fmt.Println(common.ToString(swagg))
-	fmt.Println(common.ToString(swagg.Info))
-
-	p1 := common.ToGinPath("/test/{prop1}/ddd-as/{prop2}/231_asd/{prop3}")
-	fmt.Println(p1)
-	p2 := common.ToSwaggerPath(p1)
-	fmt.Println(p2)
-
-	for k, v := range *swagg.Paths {
-		fmt.Println(k, v2m.ToShortString(v.Parameters))
-		fmt.Println(v.ToShortString())
+		logrus.Fatal(err)
 	}
 }
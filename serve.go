@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/heimbogdan/go-swagger-mock/common"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"github.com/sirupsen/logrus"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	spec := flags.String("spec", "", "swagger 2.0 spec file, directory, url or - for stdin (json or yaml)")
+	host := flags.String("host", "0.0.0.0", "address to listen on")
+	port := flags.Int("port", 8080, "port to listen on")
+	opts := common.Options{}
+	flags.BoolVar(&opts.Security, "security", false, "enforce the security requirements of the operations")
+	oauth2 := flags.Bool("oauth2", false, "serve a mock oauth2 authorization server for the oauth2 security definitions")
+	oauth2Secret := flags.String("oauth2-secret", "", "secret signing the mock oauth2 tokens, random when empty")
+	oauth2Path := flags.String("oauth2-path", common.OAuth2Path, "path the mock oauth2 authorization server is mounted on")
+	flags.BoolVar(&opts.LenientConsumes, "lenient-consumes", false, "only log requests whose content type is not consumed instead of answering 415")
+	flags.Int64Var(&opts.Seed, "seed", 0, "seed of the generated values, which also depend on the operation, path parameters and query string")
+	data := flags.String("data", "defaults", "generated values: defaults (zero values) or realistic (guessed from formats and property names)")
+	flags.IntVar(&opts.ArrayLength, "array-length", 1, "number of items of the generated arrays, within their minItems and maxItems")
+	flags.StringVar(&opts.LimitParameter, "limit-param", "limit", "query parameter setting the number of items of the response array, or of the only array property of the response object, for the operations declaring it, none when empty")
+	requestValidation := flags.String("validate-requests", "strict", "check the requests against the operations: off, lenient (log) or strict (400)")
+	responseValidation := flags.String("validate-responses", "off", "check the mocked responses against their definition: off, lenient (log) or strict (500)")
+	_ = flags.Parse(args)
+	if len(*spec) == 0 {
+		flags.Usage()
+		return errors.New("missing --spec")
+	}
+	var err error
+	if opts.RequestValidation, err = common.ParseValidationMode(*requestValidation); err != nil {
+		return err
+	}
+	if opts.ResponseValidation, err = common.ParseValidationMode(*responseValidation); err != nil {
+		return err
+	}
+	if opts.Data, err = common.ParseDataMode(*data); err != nil {
+		return err
+	}
+
+	doc, err := loadSpec(*spec)
+	if err != nil {
+		return err
+	}
+
+	engine := gin.Default()
+	var auth *common.AuthServer
+	if *oauth2 {
+		if auth, err = common.NewAuthServer(&doc.Swagger, *oauth2Secret); err != nil {
+			return err
+		}
+		opts.TokenInspector = auth.Inspect
+	}
+	common.RegisterControllers(engine, doc.Swagger.BasePath, common.CreateControllers(doc, opts))
+	if auth != nil {
+		if err = auth.Register(engine, *oauth2Path); err != nil {
+			return fmt.Errorf("%w, change --oauth2-path", err)
+		}
+		logrus.Infof("oauth2 flows %v served on %s/authorize and %s/token", auth.Flows(), *oauth2Path, *oauth2Path)
+	}
+
+	server := &http.Server{
+		Addr:    net.JoinHostPort(*host, strconv.Itoa(*port)),
+		Handler: engine,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logrus.Infof("serving %s on %s", *spec, server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serverErr:
+		return err
+	case <-ctx.Done():
+	}
+	logrus.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+func loadSpec(spec string) (*v2.Document, error) {
+	doc, err := v2.Load(spec)
+	if err != nil {
+		return nil, err
+	}
+	if !doc.Valid() {
+		for _, e := range doc.Errors {
+			fmt.Fprintf(os.Stderr, "- %s\n", e)
+		}
+		return nil, fmt.Errorf("%s is not a valid swagger 2.0 document", spec)
+	}
+	for _, e := range doc.Resolver().Unresolved() {
+		logrus.Warn(e.Error())
+	}
+	return doc, nil
+}
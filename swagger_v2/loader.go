@@ -0,0 +1,247 @@
+package swagger_v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v2"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// StdinSource is the source name used to read the spec from the standard input.
+const StdinSource = "-"
+
+// specFileNames are looked up, in order, when the source is a directory.
+var specFileNames = []string{
+	"swagger.yaml", "swagger.yml", "swagger.json",
+	"openapi.yaml", "openapi.yml", "openapi.json",
+}
+
+type Document struct {
+	Swagger models.Swagger
+	// Location is the absolute path or url the document was read from, empty for stdin.
+	Location string
+	Errors   []ValidationError
+
+	raw          interface{}
+	resolverOnce sync.Once
+	resolver     *Resolver
+}
+
+func NewDocument(swagger models.Swagger, location string) *Document {
+	return &Document{Swagger: swagger, Location: location, Errors: make([]ValidationError, 0)}
+}
+
+// Resolver returns the reference resolver shared by every user of the document.
+func (d *Document) Resolver() *Resolver {
+	d.resolverOnce.Do(func() {
+		d.resolver = NewResolver(d)
+	})
+	return d.resolver
+}
+
+func (d *Document) Valid() bool {
+	return len(d.Errors) == 0
+}
+
+type ValidationError struct {
+	Field       string
+	Description string
+}
+
+func (e ValidationError) String() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Description)
+}
+
+// Load reads a swagger 2.0 document from a file, directory, url or stdin ("-"),
+// validates it against the embedded schema and unmarshals it into models.Swagger.
+// Schema violations are collected in Document.Errors, an error is returned only when
+// the document cannot be read or decoded.
+func Load(source string) (*Document, error) {
+	data, location, err := readSource(source)
+	if err != nil {
+		return nil, err
+	}
+	return LoadBytes(data, location)
+}
+
+func LoadBytes(data []byte, location string) (*Document, error) {
+	raw, err := decode(data, location)
+	if err != nil {
+		return nil, err
+	}
+	doc := NewDocument(models.Swagger{}, location)
+	doc.raw = raw
+	result, err := SwaggerV2Schema.Validate(gojsonschema.NewGoLoader(raw))
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range result.Errors() {
+		doc.Errors = append(doc.Errors, ValidationError{Field: e.Field(), Description: e.Description()})
+	}
+	// the yaml tags of the models lack ",inline" for the embedded structs,
+	// so every document goes through its json representation
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(encoded, &doc.Swagger); err != nil {
+		return nil, fmt.Errorf("%s: %w", describe(location), err)
+	}
+	return doc, nil
+}
+
+func readSource(source string) ([]byte, string, error) {
+	if source == StdinSource {
+		data, err := io.ReadAll(os.Stdin)
+		return data, "", err
+	}
+	if isUrl(source) {
+		return fetch(source)
+	}
+	path, err := filepath.Abs(source)
+	if err != nil {
+		return nil, "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if info.IsDir() {
+		if path, err = findSpec(path); err != nil {
+			return nil, "", err
+		}
+	}
+	data, err := os.ReadFile(path)
+	return data, path, err
+}
+
+func fetch(url string) ([]byte, string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%s: unexpected status %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	return data, url, err
+}
+
+func findSpec(dir string) (string, error) {
+	for _, name := range specFileNames {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no spec file found in %s, expected one of %s", dir, strings.Join(specFileNames, ", "))
+}
+
+func isUrl(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+func isJson(data []byte, location string) bool {
+	switch strings.ToLower(filepath.Ext(location)) {
+	case ".json":
+		return true
+	case ".yaml", ".yml":
+		return false
+	}
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// decode parses json or yaml into generic maps and slices with string keys. Numbers are kept
+// as json.Number, so their literal text reaches the models without float64 rounding.
+func decode(data []byte, location string) (interface{}, error) {
+	if isJson(data, location) {
+		raw, err := DecodeJson(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid json: %w", describe(location), err)
+		}
+		return raw, nil
+	}
+	var raw yamlValue
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: invalid yaml: %w", describe(location), err)
+	}
+	return raw.value, nil
+}
+
+// DecodeJson parses json into generic maps and slices, numbers being json.Number.
+func DecodeJson(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var raw interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the top-level value")
+	}
+	return raw, nil
+}
+
+// yamlValue decodes a yaml value into generic maps with string keys and slices, keeping the
+// literal text of the numbers as json.Number.
+type yamlValue struct {
+	value interface{}
+}
+
+func (v *yamlValue) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var generic interface{}
+	if err := unmarshal(&generic); err != nil {
+		return err
+	}
+	switch generic.(type) {
+	case map[interface{}]interface{}:
+		items := make(map[interface{}]yamlValue)
+		if err := unmarshal(&items); err != nil {
+			return err
+		}
+		m := make(map[string]interface{}, len(items))
+		for key, item := range items {
+			m[fmt.Sprint(key)] = item.value
+		}
+		v.value = m
+	case []interface{}:
+		items := make([]yamlValue, 0)
+		if err := unmarshal(&items); err != nil {
+			return err
+		}
+		s := make([]interface{}, len(items))
+		for i, item := range items {
+			s[i] = item.value
+		}
+		v.value = s
+	case int, int64, uint64, float64:
+		// a scalar decoded into a string keeps its text, like 0.1 or 9007199254740993
+		var text string
+		if err := unmarshal(&text); err == nil && json.Valid([]byte(text)) {
+			v.value = json.Number(text)
+		} else {
+			v.value = generic
+		}
+	default:
+		v.value = generic
+	}
+	return nil
+}
+
+func describe(location string) string {
+	if len(location) == 0 {
+		return "stdin"
+	}
+	return location
+}
@@ -0,0 +1,139 @@
+package swagger_v2
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+const numbersJson = `{
+  "swagger": "2.0",
+  "info": {"title": "numbers", "version": "1"},
+  "paths": {
+    "/items": {
+      "get": {
+        "parameters": [
+          {"name": "id", "in": "query", "type": "integer", "maximum": 9007199254740993, "minimum": -9007199254740993},
+          {"name": "price", "in": "query", "type": "number", "maximum": 99.5, "multipleOf": 0.01, "minimum": 1e-3}
+        ],
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  }
+}`
+
+const numbersYaml = `swagger: "2.0"
+info: {title: numbers, version: "1"}
+paths:
+  /items:
+    get:
+      parameters:
+        - {name: id, in: query, type: integer, maximum: 9007199254740993, minimum: -9007199254740993}
+        - {name: price, in: query, type: number, maximum: 99.5, multipleOf: 0.01, minimum: 1e-3}
+      responses:
+        "200": {description: ok}
+`
+
+func TestLoadBytesKeepsNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		location string
+	}{
+		{name: "json", data: numbersJson, location: "spec.json"},
+		{name: "yaml", data: numbersYaml, location: "spec.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := LoadBytes([]byte(tt.data), tt.location)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !doc.Valid() {
+				t.Fatalf("unexpected errors %v", doc.Errors)
+			}
+			params := *(*doc.Swagger.Paths)["/items"].Get.Parameters
+			if len(params) != 2 {
+				t.Fatalf("got %d parameters, want 2", len(params))
+			}
+			id, price := params[0], params[1]
+			checks := []struct {
+				field string
+				got   interface{ Rat() *big.Rat }
+				want  string
+			}{
+				{field: "id maximum", got: id.Maximum, want: "9007199254740993"},
+				{field: "id minimum", got: id.Minimum, want: "-9007199254740993"},
+				{field: "price maximum", got: price.Maximum, want: "199/2"},
+				{field: "price multipleOf", got: price.MultipleOf, want: "1/100"},
+				{field: "price minimum", got: price.Minimum, want: "1/1000"},
+			}
+			for _, c := range checks {
+				if got := c.got.Rat().RatString(); got != c.want {
+					t.Errorf("%s = %s, want %s", c.field, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeJson(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "object", data: `{"a": 1.5}`},
+		{name: "trailing space", data: "[1, 2] \n"},
+		{name: "trailing value", data: `{"a": 1} {"b": 2}`, wantErr: true},
+		{name: "invalid", data: `{"a": }`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeJson([]byte(tt.data)); (err != nil) != tt.wantErr {
+				t.Errorf("DecodeJson() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+const enumsYaml = `swagger: "2.0"
+info: {title: enums, version: "1"}
+paths: {}
+definitions:
+  Size: {type: integer, enum: [1, 2, 9007199254740993]}
+  Flag: {type: boolean, enum: [true]}
+  Ratio: {type: number, enum: [0.5, 1e-3]}
+  Color: {type: string, enum: [red, "1"]}
+`
+
+func TestLoadBytesKeepsEnums(t *testing.T) {
+	doc, err := LoadBytes([]byte(enumsYaml), "spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Valid() {
+		t.Fatalf("unexpected errors %v", doc.Errors)
+	}
+	tests := []struct {
+		definition string
+		want       []interface{}
+	}{
+		{definition: "Size", want: []interface{}{json.Number("1"), json.Number("2"), json.Number("9007199254740993")}},
+		{definition: "Flag", want: []interface{}{true}},
+		{definition: "Ratio", want: []interface{}{json.Number("0.5"), json.Number("1e-3")}},
+		{definition: "Color", want: []interface{}{"red", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.definition, func(t *testing.T) {
+			schema := (*doc.Swagger.Definitions)[tt.definition]
+			if schema.Enum == nil {
+				t.Fatal("enum not loaded")
+			}
+			if got := []interface{}(*schema.Enum); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("enum = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
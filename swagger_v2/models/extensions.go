@@ -0,0 +1,209 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+)
+
+var vendorExtensionRegex = regexp.MustCompile(VendorExtensionPattern)
+
+// Extensions holds the vendor extensions, the "x-" properties, of an object.
+type Extensions map[string]interface{}
+
+// Get returns the value of an extension, with or without its "x-" prefix.
+func (e Extensions) Get(name string) (interface{}, bool) {
+	if !vendorExtensionRegex.MatchString(name) {
+		name = "x-" + name
+	}
+	value, ok := e[name]
+	return value, ok
+}
+
+// GetString returns the value of a string extension.
+func (e Extensions) GetString(name string) (string, bool) {
+	value, ok := e.Get(name)
+	if !ok {
+		return "", false
+	}
+	s, ok := value.(string)
+	return s, ok
+}
+
+// GetInt returns the value of an integer extension.
+func (e Extensions) GetInt(name string) (int64, bool) {
+	value, ok := e.Get(name)
+	if !ok {
+		return 0, false
+	}
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v), true
+		}
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// unmarshalExtensions collects the vendor extensions of a json object, nil when it has none.
+func unmarshalExtensions(data []byte) (Extensions, error) {
+	properties := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+	var extensions Extensions
+	for name, raw := range properties {
+		if !vendorExtensionRegex.MatchString(name) {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		if extensions == nil {
+			extensions = make(Extensions)
+		}
+		extensions[name] = value
+	}
+	return extensions, nil
+}
+
+// marshalExtensions adds the vendor extensions to the json object of a value.
+func marshalExtensions(value interface{}, extensions Extensions) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil || len(extensions) == 0 {
+		return data, err
+	}
+	properties := make(map[string]interface{})
+	if err = json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+	for name, v := range extensions {
+		properties[name] = v
+	}
+	return json.Marshal(properties)
+}
+
+func (s *Swagger) UnmarshalJSON(data []byte) error {
+	type plain Swagger
+	data, err := withoutPathsExtensions(data)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+	s.Extensions, err = unmarshalExtensions(data)
+	return err
+}
+
+// withoutPathsExtensions drops the vendor extensions of the paths object, which are not path items.
+func withoutPathsExtensions(data []byte) ([]byte, error) {
+	document := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	paths := make(map[string]json.RawMessage)
+	if raw, ok := document["paths"]; !ok || json.Unmarshal(raw, &paths) != nil {
+		return data, nil
+	}
+	found := false
+	for name := range paths {
+		if vendorExtensionRegex.MatchString(name) {
+			delete(paths, name)
+			found = true
+		}
+	}
+	if !found {
+		return data, nil
+	}
+	raw, err := json.Marshal(paths)
+	if err != nil {
+		return nil, err
+	}
+	document["paths"] = raw
+	return json.Marshal(document)
+}
+
+func (s Swagger) MarshalJSON() ([]byte, error) {
+	type plain Swagger
+	return marshalExtensions(plain(s), s.Extensions)
+}
+
+func (pi *PathItem) UnmarshalJSON(data []byte) error {
+	type plain PathItem
+	if err := json.Unmarshal(data, (*plain)(pi)); err != nil {
+		return err
+	}
+	extensions, err := unmarshalExtensions(data)
+	pi.Extensions = extensions
+	return err
+}
+
+func (pi PathItem) MarshalJSON() ([]byte, error) {
+	type plain PathItem
+	return marshalExtensions(plain(pi), pi.Extensions)
+}
+
+func (op *Operation) UnmarshalJSON(data []byte) error {
+	type plain Operation
+	if err := json.Unmarshal(data, (*plain)(op)); err != nil {
+		return err
+	}
+	extensions, err := unmarshalExtensions(data)
+	op.Extensions = extensions
+	return err
+}
+
+func (op Operation) MarshalJSON() ([]byte, error) {
+	type plain Operation
+	return marshalExtensions(plain(op), op.Extensions)
+}
+
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	type plain Parameter
+	if err := json.Unmarshal(data, (*plain)(p)); err != nil {
+		return err
+	}
+	extensions, err := unmarshalExtensions(data)
+	p.Extensions = extensions
+	return err
+}
+
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	type plain Parameter
+	return marshalExtensions(plain(p), p.Extensions)
+}
+
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type plain Response
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	extensions, err := unmarshalExtensions(data)
+	r.Extensions = extensions
+	return err
+}
+
+func (r Response) MarshalJSON() ([]byte, error) {
+	type plain Response
+	return marshalExtensions(plain(r), r.Extensions)
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type plain Schema
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+	extensions, err := unmarshalExtensions(data)
+	s.Extensions = extensions
+	return err
+}
+
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	return marshalExtensions(plain(s), s.Extensions)
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Number is an arbitrary precision json number, keeping decimals like 0.01 exact.
+type Number struct {
+	value big.Rat
+	// text is the number as written in the document.
+	text string
+}
+
+// NewNumber parses a decimal number, like "99.5" or "1e-3".
+func NewNumber(text string) (*Number, error) {
+	n := &Number{text: text}
+	if _, ok := n.value.SetString(text); !ok {
+		return nil, fmt.Errorf("invalid number %q", text)
+	}
+	return n, nil
+}
+
+// Rat returns a copy of the exact value.
+func (n *Number) Rat() *big.Rat {
+	return new(big.Rat).Set(&n.value)
+}
+
+// Float64 returns the nearest float64 value.
+func (n *Number) Float64() float64 {
+	f, _ := n.value.Float64()
+	return f
+}
+
+// Cmp compares the number to another one, returning -1, 0 or +1.
+func (n *Number) Cmp(other *Number) int {
+	return n.value.Cmp(&other.value)
+}
+
+func (n *Number) String() string {
+	return n.text
+}
+
+func (n *Number) UnmarshalJSON(data []byte) error {
+	parsed, err := NewNumber(string(data))
+	if err != nil {
+		return err
+	}
+	n.value.Set(&parsed.value)
+	n.text = parsed.text
+	return nil
+}
+
+func (n *Number) MarshalJSON() ([]byte, error) {
+	return []byte(n.text), nil
+}
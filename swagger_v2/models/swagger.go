@@ -37,6 +37,7 @@ type Swagger struct {
 	SecurityDefinitions *map[string]Security   `json:"securityDefinitions,omitempty" yaml:"securityDefinitions,omitempty"`
 	Tags                *[]Tag                 `json:"tags,omitempty" yaml:"tags,omitempty"`
 	ExternalDocs        *ExternalDocs          `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+	Extensions          Extensions             `json:"-" yaml:"-"`
 }
 
 type Info struct {
@@ -77,6 +78,7 @@ type Operation struct {
 	Schemes      *[]Schema              `json:"schemes,omitempty" yaml:"schemes,omitempty"`
 	Deprecated   *bool                  `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
 	Security     *[]SecurityRequirement `json:"security,omitempty" yaml:"security,omitempty"`
+	Extensions   Extensions             `json:"-" yaml:"-"`
 }
 
 func (op *Operation) GetQueryParameters() []Parameter {
@@ -125,6 +127,7 @@ type PathItem struct {
 	Head       *Operation   `json:"head,omitempty" yaml:"head,omitempty"`
 	Patch      *Operation   `json:"patch,omitempty" yaml:"patch,omitempty"`
 	Parameters *[]Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Extensions Extensions   `json:"-" yaml:"-"`
 }
 
 func (pi *PathItem) ToShortString() string {
@@ -160,6 +163,15 @@ type Response struct {
 	Schema      *Schema                 `json:"schema,omitempty" yaml:"schema,omitempty"`
 	Headers     *map[string]Header      `json:"headers,omitempty" yaml:"headers,omitempty"`
 	Examples    *map[string]interface{} `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Extensions  Extensions              `json:"-" yaml:"-"`
+}
+
+func (r *Response) GetRefName() string {
+	if r.Ref != nil {
+		matcher := regexp.MustCompile(refNameRegex)
+		return string(matcher.ReplaceAll([]byte(*r.Ref), []byte(`$1`)))
+	}
+	return ""
 }
 
 type Header struct {
@@ -182,6 +194,7 @@ type Parameter struct {
 	Items            *PrimitivesItems `json:"items,omitempty" yaml:"items,omitempty"`
 	CollectionFormat *string          `json:"collectionFormat,omitempty" yaml:"collectionFormat,omitempty"`
 	AllowEmptyValue  *bool            `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
+	Extensions       Extensions       `json:"-" yaml:"-"`
 }
 
 func (p *Parameter) GetLocationAndName() string {
@@ -306,11 +319,13 @@ type Schema struct {
 	MaxProperties        *int               `json:"maxProperties,omitempty" yaml:"maxProperties,omitempty"`
 	MinProperties        *int               `json:"minProperties,omitempty" yaml:"minProperties,omitempty"`
 	AdditionalProperties interface{}        `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"` //TODO check if is Schema or boolean
-	Items                interface{}        `json:"items,omitempty" yaml:"items,omitempty"`                               // TODO will check if array of Schema
+	Items                interface{}        `json:"items,omitempty" yaml:"items,omitempty"`                               // a Schema or a list of positional Schemas
+	AdditionalItems      interface{}        `json:"additionalItems,omitempty" yaml:"additionalItems,omitempty"`           // a Schema or a boolean, for tuple items
 	AllOf                *[]Schema          `json:"allOf,omitempty" yaml:"allOf,omitempty"`
 	Properties           *map[string]Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
 	Discriminator        *string            `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`
 	Xml                  *Xml               `json:"xml,omitempty" yaml:"xml,omitempty"`
+	Extensions           Extensions         `json:"-" yaml:"-"`
 }
 
 func (s *Schema) GetItems() (arr []Schema) {
@@ -320,7 +335,7 @@ func (s *Schema) GetItems() (arr []Schema) {
 			logrus.Error(err)
 		} else {
 			switch s.Items.(type) {
-			case map[string]interface{}:
+			case map[string]interface{}, Schema, *Schema:
 				sObj := Schema{}
 				err = json.Unmarshal([]byte(data), &sObj)
 				if err != nil {
@@ -328,7 +343,7 @@ func (s *Schema) GetItems() (arr []Schema) {
 				} else {
 					return append(arr, sObj)
 				}
-			case []map[string]interface{}:
+			case []interface{}, []map[string]interface{}, []Schema:
 				var sObj []Schema
 				err = json.Unmarshal([]byte(data), &sObj)
 				if err != nil {
@@ -345,6 +360,35 @@ func (s *Schema) GetItems() (arr []Schema) {
 	return nil
 }
 
+// GetAdditionalItems returns the schema of the items following tuple items, nil when there is
+// none or additionalItems is a boolean.
+func (s *Schema) GetAdditionalItems() *Schema {
+	if _, ok := s.AdditionalItems.(map[string]interface{}); !ok {
+		return nil
+	}
+	data, err := ToString(s.AdditionalItems)
+	if err != nil {
+		logrus.Error(err)
+		return nil
+	}
+	additional := &Schema{}
+	if err = json.Unmarshal([]byte(data), additional); err != nil {
+		logrus.Error(err)
+		return nil
+	}
+	return additional
+}
+
+// HasTupleItems tells whether the items are a list of positional schemas, the first item
+// matching the first schema and so on, rather than one schema for every item.
+func (s *Schema) HasTupleItems() bool {
+	switch s.Items.(type) {
+	case []interface{}, []map[string]interface{}, []Schema:
+		return true
+	}
+	return false
+}
+
 func ToString[T any](i T) (string, error) {
 	data, err := json.Marshal(i)
 	if err != nil {
@@ -360,7 +404,6 @@ type FileSchema struct {
 	Required     *[]string     `json:"required,omitempty" yaml:"required,omitempty"`
 	ReadOnly     *bool         `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
 	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
-	Enum         *[]string     `json:"enum,omitempty" yaml:"enum,omitempty"`
 }
 
 type PrimitivesItems struct {
@@ -376,7 +419,7 @@ type Xml struct {
 	Name      *string `json:"name,omitempty" yaml:"name,omitempty"`
 	Namespace *string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 	Prefix    *string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
-	Attribute *string `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+	Attribute *bool   `json:"attribute,omitempty" yaml:"attribute,omitempty"`
 	Wrapped   *bool   `json:"wrapped,omitempty" yaml:"wrapped,omitempty"`
 }
 
@@ -416,16 +459,25 @@ func (t *TypeStruct) GetTypeAndFormat() string {
 }
 
 type Restrictions struct {
-	Maximum          *int      `json:"maximum,omitempty" yaml:"maximum,omitempty"`
-	ExclusiveMaximum *bool     `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
-	Minimum          *int      `json:"minimum,omitempty" yaml:"minimum,omitempty"`
-	ExclusiveMinimum *bool     `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
-	MaxLength        *int      `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
-	MinLength        *int      `json:"minLength,omitempty" yaml:"minLength,omitempty"`
-	Pattern          *string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`
-	MaxItems         *int      `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
-	MinItems         *int      `json:"minItems,omitempty" yaml:"minItems,omitempty"`
-	UniqueItems      *bool     `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
-	Enum             *[]string `json:"enum,omitempty" yaml:"enum,omitempty"`
-	MultipleOf       *int      `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+	Maximum          *Number `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMaximum *bool   `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	Minimum          *Number `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	ExclusiveMinimum *bool   `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	MaxLength        *int    `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinLength        *int    `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	Pattern          *string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	MaxItems         *int    `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	MinItems         *int    `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	UniqueItems      *bool   `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
+	Enum             *Enum   `json:"enum,omitempty" yaml:"enum,omitempty"`
+	MultipleOf       *Number `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+}
+
+// Enum holds the json values of an enum, numbers being kept as json.Number.
+type Enum []interface{}
+
+func (e *Enum) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode((*[]interface{})(e))
 }
@@ -0,0 +1,242 @@
+package swagger_v2
+
+import (
+	"fmt"
+	"github.com/xeipuuv/gojsonschema"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var namedGroupRegex = regexp.MustCompile(`\(\?P<[^>]*>`)
+
+func init() {
+	// the spec patterns are ECMA-262 regular expressions, accept the ones go can approximate
+	gojsonschema.FormatCheckers.Add("regex", regexFormatChecker{})
+}
+
+type regexFormatChecker struct{}
+
+func (regexFormatChecker) IsFormat(input interface{}) bool {
+	pattern, ok := input.(string)
+	if !ok {
+		return true
+	}
+	_, _, ok = ApproximatePattern(pattern)
+	return ok
+}
+
+// ApproximatePattern converts an ECMA-262 pattern, the dialect of the swagger patterns, to a go
+// regular expression. Named groups and \u escapes are translated. Lookarounds, which go lacks,
+// are dropped and backreferences replaced by the group they repeat, so the approximation
+// matches every value the pattern matches, and possibly more: exact is then false. ok is false
+// when the pattern cannot be converted.
+func ApproximatePattern(pattern string) (approximation string, exact bool, ok bool) {
+	if _, err := regexp.Compile(pattern); err == nil {
+		return pattern, true, true
+	}
+	t := &patternTranslator{src: pattern, exact: true, groups: make(map[int]string), named: make(map[string]int)}
+	approximation = t.translate()
+	if t.err != nil {
+		return "", false, false
+	}
+	if _, err := regexp.Compile(approximation); err != nil {
+		return "", false, false
+	}
+	return approximation, t.exact, true
+}
+
+type patternTranslator struct {
+	src   string
+	pos   int
+	out   strings.Builder
+	exact bool
+	err   error
+	// groups holds the translated content of the closed capture groups, by number.
+	groups map[int]string
+	named  map[string]int
+	count  int
+}
+
+type openGroup struct {
+	// number is the capture group number, 0 for other groups.
+	number int
+	// start is the output offset of the group content.
+	start int
+}
+
+func (t *patternTranslator) translate() string {
+	stack := make([]openGroup, 0)
+	for t.pos < len(t.src) && t.err == nil {
+		c := t.src[t.pos]
+		switch {
+		case c == '\\':
+			t.escape()
+		case c == '[':
+			t.class()
+		case c == '(' && t.lookaround():
+			t.exact = false
+			t.skipGroup()
+		case c == '(':
+			group := openGroup{}
+			switch {
+			case strings.HasPrefix(t.src[t.pos:], "(?<"):
+				end := strings.IndexByte(t.src[t.pos:], '>')
+				if end < 0 {
+					t.err = fmt.Errorf("unterminated group name")
+					break
+				}
+				name := t.src[t.pos+3 : t.pos+end]
+				t.count++
+				group.number = t.count
+				t.named[name] = t.count
+				t.out.WriteString("(?P<" + name + ">")
+				t.pos += end + 1
+			case strings.HasPrefix(t.src[t.pos:], "(?"):
+				t.out.WriteString("(?")
+				t.pos += 2
+			default:
+				t.count++
+				group.number = t.count
+				t.out.WriteByte('(')
+				t.pos++
+			}
+			group.start = t.out.Len()
+			stack = append(stack, group)
+		case c == ')':
+			if len(stack) > 0 {
+				group := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if group.number > 0 {
+					t.groups[group.number] = t.out.String()[group.start:]
+				}
+			}
+			t.out.WriteByte(')')
+			t.pos++
+		default:
+			t.out.WriteByte(c)
+			t.pos++
+		}
+	}
+	return t.out.String()
+}
+
+// lookaround tells whether the group at the position is a lookahead or a lookbehind.
+func (t *patternTranslator) lookaround() bool {
+	rest := t.src[t.pos:]
+	return strings.HasPrefix(rest, "(?=") || strings.HasPrefix(rest, "(?!") ||
+		strings.HasPrefix(rest, "(?<=") || strings.HasPrefix(rest, "(?<!")
+}
+
+// skipGroup moves past the group at the position, with its nested groups and classes.
+func (t *patternTranslator) skipGroup() {
+	depth := 0
+	for t.pos < len(t.src) {
+		switch t.src[t.pos] {
+		case '\\':
+			t.pos++
+		case '[':
+			for t.pos++; t.pos < len(t.src) && t.src[t.pos] != ']'; t.pos++ {
+				if t.src[t.pos] == '\\' {
+					t.pos++
+				}
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				t.pos++
+				return
+			}
+		}
+		t.pos++
+	}
+	t.err = fmt.Errorf("unterminated group")
+}
+
+// class copies a character class, translating its escapes.
+func (t *patternTranslator) class() {
+	t.out.WriteByte('[')
+	t.pos++
+	if strings.HasPrefix(t.src[t.pos:], "^") {
+		t.out.WriteByte('^')
+		t.pos++
+	}
+	// a leading ] is a literal in go, ecma classes cannot contain it unescaped
+	for t.pos < len(t.src) && t.src[t.pos] != ']' && t.err == nil {
+		if t.src[t.pos] == '\\' {
+			t.escape()
+			continue
+		}
+		t.out.WriteByte(t.src[t.pos])
+		t.pos++
+	}
+	if t.pos >= len(t.src) {
+		t.err = fmt.Errorf("unterminated character class")
+		return
+	}
+	t.out.WriteByte(']')
+	t.pos++
+}
+
+// escape translates the escape sequence at the position.
+func (t *patternTranslator) escape() {
+	if t.pos+1 >= len(t.src) {
+		t.err = fmt.Errorf("trailing backslash")
+		return
+	}
+	c := t.src[t.pos+1]
+	rest := t.src[t.pos+2:]
+	switch {
+	case c >= '1' && c <= '9':
+		end := 0
+		for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+			end++
+		}
+		n, _ := strconv.Atoi(t.src[t.pos+1 : t.pos+2+end])
+		t.backreference(n)
+		t.pos += 2 + end
+	case c == 'k' && strings.HasPrefix(rest, "<"):
+		end := strings.IndexByte(rest, '>')
+		if end < 0 {
+			t.err = fmt.Errorf("unterminated group name")
+			return
+		}
+		t.backreference(t.named[rest[1:end]])
+		t.pos += 2 + end + 1
+	case c == 'u' && strings.HasPrefix(rest, "{"):
+		end := strings.IndexByte(rest, '}')
+		if end < 0 {
+			t.err = fmt.Errorf("unterminated unicode escape")
+			return
+		}
+		t.out.WriteString(`\x{` + rest[1:end] + `}`)
+		t.pos += 2 + end + 1
+	case c == 'u' && len(rest) >= 4:
+		t.out.WriteString(`\x{` + rest[:4] + `}`)
+		t.pos += 6
+	case c == 'c' && len(rest) >= 1:
+		t.out.WriteString(fmt.Sprintf(`\x{%x}`, rest[0]%32))
+		t.pos += 3
+	case c == '0':
+		t.out.WriteString(`\x00`)
+		t.pos += 2
+	case c == '/':
+		t.out.WriteByte('/')
+		t.pos += 2
+	default:
+		t.out.WriteString(t.src[t.pos : t.pos+2])
+		t.pos += 2
+	}
+}
+
+// backreference writes the content of a closed group in place of a reference to it, a
+// reference to another group matching the empty string.
+func (t *patternTranslator) backreference(n int) {
+	t.exact = false
+	if content, ok := t.groups[n]; ok {
+		// go refuses duplicate group names
+		t.out.WriteString("(?:" + namedGroupRegex.ReplaceAllString(content, "(?:") + ")")
+	}
+}
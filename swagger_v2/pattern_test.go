@@ -0,0 +1,60 @@
+package swagger_v2
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestApproximatePattern(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		want      string
+		wantExact bool
+		wantOk    bool
+		matches   []string
+	}{
+		{name: "go compatible", pattern: `^\d{3}-[a-z]+$`, want: `^\d{3}-[a-z]+$`, wantExact: true, wantOk: true},
+		{name: "lookaheads", pattern: `^(?=.*[A-Z])(?=.*\d)[A-Za-z\d]{8,}$`, want: `^[A-Za-z\d]{8,}$`, wantOk: true, matches: []string{"Passw0rd"}},
+		{name: "negative lookahead", pattern: `^(?!admin$)[a-z]+$`, want: `^[a-z]+$`, wantOk: true, matches: []string{"root"}},
+		{name: "lookbehind", pattern: `(?<=\$)\d+`, want: `\d+`, wantOk: true, matches: []string{"$42"}},
+		{name: "backreference", pattern: `^(['"]).*\1$`, want: `^(['"]).*(?:['"])$`, wantOk: true, matches: []string{`"quoted"`}},
+		{name: "named group and reference", pattern: `^(?<q>x+)-\k<q>$`, want: `^(?P<q>x+)-(?:x+)$`, wantOk: true, matches: []string{"xx-xx"}},
+		{name: "nested named group reference", pattern: `^((?<d>\d))\1$`, want: `^((?P<d>\d))(?:(?:\d))$`, wantOk: true, matches: []string{"11"}},
+		{name: "named group", pattern: `^(?<year>\d{4})$(?=)`, want: `^(?P<year>\d{4})$`, wantOk: true, matches: []string{"2024"}},
+		{name: "unicode escapes", pattern: `^\u00e9\u{1F600}(?!x)$`, want: `^\x{00e9}\x{1F600}$`, wantOk: true, matches: []string{"é😀"}},
+		{name: "escaped slash in a class", pattern: `^[\/A](?!b)$`, want: `^[/A]$`, wantOk: true, matches: []string{"/", "A"}},
+		{name: "lookahead with a class holding a paren", pattern: `^(?=[)(])..$`, want: `^..$`, wantOk: true, matches: []string{"()"}},
+		{name: "unterminated group", pattern: `^(?=abc$`, wantOk: false},
+		{name: "unterminated class", pattern: `^[a-z(?=x)`, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, exact, ok := ApproximatePattern(tt.pattern)
+			if ok != tt.wantOk {
+				t.Fatalf("ApproximatePattern(%q) ok = %v, want %v", tt.pattern, ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if got != tt.want || exact != tt.wantExact {
+				t.Errorf("ApproximatePattern(%q) = %q, %v, want %q, %v", tt.pattern, got, exact, tt.want, tt.wantExact)
+			}
+			re := regexp.MustCompile(got)
+			for _, value := range tt.matches {
+				if !re.MatchString(value) {
+					t.Errorf("%q does not match %q", value, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRegexFormatChecker(t *testing.T) {
+	checker := regexFormatChecker{}
+	for pattern, want := range map[string]bool{`^a+$`: true, `^(?=a)a$`: true, `^(a`: false} {
+		if got := checker.IsFormat(pattern); got != want {
+			t.Errorf("IsFormat(%q) = %v, want %v", pattern, got, want)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package swagger_v2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/heimbogdan/go-swagger-mock/swagger_v2/models"
+	"github.com/xeipuuv/gojsonpointer"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const refKey = "$ref"
+
+// RefError describes a reference that could not be resolved.
+type RefError struct {
+	Ref string
+	// Location is the json pointer of the object holding the reference.
+	Location string
+	Reason   string
+}
+
+func (e *RefError) Error() string {
+	if len(e.Location) > 0 {
+		return fmt.Sprintf("%s: cannot resolve %s: %s", e.Location, e.Ref, e.Reason)
+	}
+	return fmt.Sprintf("cannot resolve %s: %s", e.Ref, e.Reason)
+}
+
+// Resolver follows json references within the document and into external files
+// relative to the document location.
+type Resolver struct {
+	base     string
+	root     interface{}
+	mu       sync.Mutex
+	external map[string]interface{}
+}
+
+func NewResolver(doc *Document) *Resolver {
+	root := doc.raw
+	if root == nil {
+		data, _ := json.Marshal(doc.Swagger)
+		root, _ = DecodeJson(data)
+	}
+	return &Resolver{
+		base:     doc.Location,
+		root:     root,
+		external: make(map[string]interface{}),
+	}
+}
+
+func (r *Resolver) Schema(s *models.Schema) (*models.Schema, error) {
+	if s == nil || s.Ref == nil {
+		return s, nil
+	}
+	resolved := &models.Schema{}
+	return resolved, r.resolveInto(*s.Ref, resolved)
+}
+
+func (r *Resolver) Parameter(p *models.Parameter) (*models.Parameter, error) {
+	if p == nil || p.Ref == nil {
+		return p, nil
+	}
+	resolved := &models.Parameter{}
+	return resolved, r.resolveInto(*p.Ref, resolved)
+}
+
+func (r *Resolver) Response(resp *models.Response) (*models.Response, error) {
+	if resp == nil || resp.Ref == nil {
+		return resp, nil
+	}
+	resolved := &models.Response{}
+	return resolved, r.resolveInto(*resp.Ref, resolved)
+}
+
+func (r *Resolver) PathItem(pi *models.PathItem) (*models.PathItem, error) {
+	if pi == nil || pi.Ref == nil {
+		return pi, nil
+	}
+	resolved := &models.PathItem{}
+	return resolved, r.resolveInto(*pi.Ref, resolved)
+}
+
+// Unresolved walks the whole document, and every external document it references,
+// and reports each reference that cannot be resolved.
+func (r *Resolver) Unresolved() []RefError {
+	errs := make([]RefError, 0)
+	visited := make(map[string]bool)
+	r.walk(r.root, r.base, "#", visited, &errs)
+	return errs
+}
+
+func (r *Resolver) walk(node interface{}, location string, pointer string, visited map[string]bool, errs *[]RefError) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := v[refKey].(string); ok {
+			if _, err := r.follow(ref, location); err != nil {
+				*errs = append(*errs, RefError{Ref: ref, Location: r.describe(location, pointer), Reason: err.Error()})
+			} else if value, target, _ := r.lookup(ref, location); target != r.base && !visited[target+"#"+fragment(ref)] {
+				visited[target+"#"+fragment(ref)] = true
+				r.walk(value, target, "#"+fragment(ref), visited, errs)
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			r.walk(v[key], location, pointer+"/"+EscapePointer(key), visited, errs)
+		}
+	case []interface{}:
+		for i, item := range v {
+			r.walk(item, location, fmt.Sprintf("%s/%d", pointer, i), visited, errs)
+		}
+	}
+}
+
+func (r *Resolver) resolveInto(ref string, target interface{}) error {
+	value, err := r.Resolve(ref)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// Resolve returns the generic value a reference from the main document points to,
+// following chained references. References nested in values loaded from external
+// files are rewritten relative to the main document.
+func (r *Resolver) Resolve(ref string) (interface{}, error) {
+	value, err := r.follow(ref, r.base)
+	if err != nil {
+		return nil, &RefError{Ref: ref, Reason: err.Error()}
+	}
+	return value, nil
+}
+
+func (r *Resolver) follow(ref string, location string) (interface{}, error) {
+	seen := make(map[string]bool)
+	for {
+		value, target, err := r.lookup(ref, location)
+		if err != nil {
+			return nil, err
+		}
+		key := target + "#" + fragment(ref)
+		if seen[key] {
+			return nil, errors.New("circular reference")
+		}
+		seen[key] = true
+		if target != r.base {
+			value = r.rebase(value, target)
+		}
+		next, ok := value.(map[string]interface{})
+		if !ok {
+			return value, nil
+		}
+		nextRef, ok := next[refKey].(string)
+		if !ok {
+			return value, nil
+		}
+		ref, location = nextRef, r.base
+	}
+}
+
+// lookup finds the value pointed by ref, relative to the document at location,
+// and returns it with the location of the document holding it.
+func (r *Resolver) lookup(ref string, location string) (interface{}, string, error) {
+	file, frag := ref, ""
+	if i := strings.Index(ref, "#"); i >= 0 {
+		file, frag = ref[:i], ref[i+1:]
+	}
+	target := location
+	if len(file) > 0 {
+		target = join(location, file)
+	}
+	doc := r.root
+	if target != r.base {
+		var err error
+		if doc, err = r.load(target); err != nil {
+			return nil, target, err
+		}
+	}
+	if unescaped, err := url.PathUnescape(frag); err == nil {
+		frag = unescaped
+	}
+	pointer, err := gojsonpointer.NewJsonPointer(frag)
+	if err != nil {
+		return nil, target, err
+	}
+	value, _, err := pointer.Get(doc)
+	if err != nil {
+		return nil, target, err
+	}
+	return value, target, nil
+}
+
+func (r *Resolver) load(location string) (interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if doc, ok := r.external[location]; ok {
+		return doc, nil
+	}
+	data, _, err := readSource(location)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decode(data, location)
+	if err != nil {
+		return nil, err
+	}
+	r.external[location] = doc
+	return doc, nil
+}
+
+// rebase copies a value loaded from an external document, making its references
+// usable from the main document.
+func (r *Resolver) rebase(value interface{}, location string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if ref, ok := item.(string); ok && key == refKey {
+				file := location
+				if i := strings.Index(ref, "#"); i > 0 {
+					file = join(location, ref[:i])
+				} else if i < 0 {
+					file = join(location, ref)
+				}
+				m[key] = file + "#" + fragment(ref)
+			} else {
+				m[key] = r.rebase(item, location)
+			}
+		}
+		return m
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, item := range v {
+			arr[i] = r.rebase(item, location)
+		}
+		return arr
+	}
+	return value
+}
+
+// join resolves a file reference against the location of the referencing document.
+func join(location string, file string) string {
+	if isUrl(file) || filepath.IsAbs(file) {
+		return file
+	}
+	if isUrl(location) {
+		if base, err := url.Parse(location); err == nil {
+			if rel, err := url.Parse(file); err == nil {
+				return base.ResolveReference(rel).String()
+			}
+		}
+		return path.Join(path.Dir(location), file)
+	}
+	if len(location) == 0 {
+		abs, _ := filepath.Abs(file)
+		return abs
+	}
+	return filepath.Join(filepath.Dir(location), filepath.FromSlash(file))
+}
+
+func fragment(ref string) string {
+	if i := strings.Index(ref, "#"); i >= 0 {
+		return ref[i+1:]
+	}
+	return ""
+}
+
+// EscapePointer escapes a json pointer reference token.
+func EscapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func (r *Resolver) describe(location string, pointer string) string {
+	if location == r.base {
+		return pointer
+	}
+	return location + pointer
+}
@@ -0,0 +1,183 @@
+package swagger_v2
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const resolverSpec = `swagger: "2.0"
+info: {title: refs, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200": {description: ok, schema: {$ref: "#/definitions/Alias"}}
+        "404": {description: missing, schema: {$ref: "#/definitions/Missing"}}
+        "500": {description: external, schema: {$ref: "models/pet.yaml#/Broken"}}
+definitions:
+  Pet: {type: object, description: local pet}
+  Alias: {$ref: "#/definitions/Pet"}
+  Remote: {$ref: "models/pet.yaml#/Owner"}
+  Shelf: {$ref: "models/pet.yaml#/Shelf"}
+  Loop: {$ref: "#/definitions/Loop2"}
+  Loop2: {$ref: "#/definitions/Loop"}
+  a/b: {type: string, description: escaped}
+`
+
+const resolverModels = `Pet: {type: object, description: external pet}
+Owner:
+  type: object
+  properties:
+    pet: {$ref: "#/Pet"}
+    tag: {$ref: "common.yaml#/Tag"}
+Broken: {$ref: "#/Nowhere"}
+Shelf:
+  type: object
+  properties:
+    tag: {$ref: "common.yaml#/Missing"}
+`
+
+const resolverCommon = `Tag: {type: string, description: common tag}
+`
+
+func loadResolverSpec(t *testing.T) (*Document, string) {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"swagger.yaml":       resolverSpec,
+		"models/pet.yaml":    resolverModels,
+		"models/common.yaml": resolverCommon,
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	doc, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc, dir
+}
+
+func TestResolverResolve(t *testing.T) {
+	doc, dir := loadResolverSpec(t)
+	models := filepath.Join(dir, "models")
+	tests := []struct {
+		name            string
+		ref             string
+		wantDescription string
+		wantRefs        map[string]string
+		wantErr         string
+	}{
+		{name: "local", ref: "#/definitions/Pet", wantDescription: "local pet"},
+		{name: "chained", ref: "#/definitions/Alias", wantDescription: "local pet"},
+		{name: "escaped token", ref: "#/definitions/a~1b", wantDescription: "escaped"},
+		{name: "external", ref: "models/pet.yaml#/Pet", wantDescription: "external pet"},
+		{
+			name: "external rebased",
+			ref:  "#/definitions/Remote",
+			wantRefs: map[string]string{
+				"pet": filepath.Join(models, "pet.yaml") + "#/Pet",
+				"tag": filepath.Join(models, "common.yaml") + "#/Tag",
+			},
+		},
+		{name: "circular", ref: "#/definitions/Loop", wantErr: "circular reference"},
+		{name: "missing pointer", ref: "#/definitions/Missing", wantErr: "Missing"},
+		{name: "missing file", ref: "models/none.yaml#/Pet", wantErr: "none.yaml"},
+		{name: "missing external pointer", ref: "models/pet.yaml#/Broken", wantErr: "Nowhere"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := doc.Resolver().Resolve(tt.ref)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Resolve(%q) error = %v, want it to mention %q", tt.ref, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve(%q) unexpected error %v", tt.ref, err)
+			}
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				t.Fatalf("Resolve(%q) = %v, want an object", tt.ref, value)
+			}
+			if description, _ := object["description"].(string); description != tt.wantDescription {
+				t.Errorf("Resolve(%q) description = %q, want %q", tt.ref, description, tt.wantDescription)
+			}
+			for property, want := range tt.wantRefs {
+				properties, _ := object["properties"].(map[string]interface{})
+				schema, _ := properties[property].(map[string]interface{})
+				if got := schema[refKey]; got != want {
+					t.Errorf("property %s $ref = %v, want %s", property, got, want)
+				}
+				if _, err := doc.Resolver().Resolve(want); err != nil {
+					t.Errorf("rebased %s does not resolve: %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestResolverSchema(t *testing.T) {
+	doc, _ := loadResolverSpec(t)
+	response := (*(*doc.Swagger.Paths)["/pets"].Get.Responses)["200"]
+	schema, err := doc.Resolver().Schema(response.Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Type == nil || *schema.Type != "object" || schema.Ref != nil {
+		t.Errorf("Schema() = %+v, want the object behind the references", schema)
+	}
+}
+
+func TestResolverUnresolved(t *testing.T) {
+	doc, dir := loadResolverSpec(t)
+	got := make(map[string]string)
+	for _, e := range doc.Resolver().Unresolved() {
+		got[e.Location] = e.Ref
+	}
+	want := map[string]string{
+		"#/definitions/Loop":                                                "#/definitions/Loop2",
+		"#/definitions/Loop2":                                               "#/definitions/Loop",
+		"#/paths/~1pets/get/responses/404/schema":                           "#/definitions/Missing",
+		"#/paths/~1pets/get/responses/500/schema":                           "models/pet.yaml#/Broken",
+		filepath.Join(dir, "models", "pet.yaml") + "#/Shelf/properties/tag": "common.yaml#/Missing",
+	}
+	if len(got) != len(want) {
+		t.Errorf("Unresolved() = %v, want %v", got, want)
+	}
+	for location, ref := range want {
+		if got[location] != ref {
+			t.Errorf("Unresolved() at %s = %q, want %q", location, got[location], ref)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		location string
+		file     string
+		want     string
+	}{
+		{location: "/specs/swagger.yaml", file: "models/pet.yaml", want: "/specs/models/pet.yaml"},
+		{location: "/specs/models/pet.yaml", file: "../common.yaml", want: "/specs/common.yaml"},
+		{location: "/specs/swagger.yaml", file: "/shared/pet.yaml", want: "/shared/pet.yaml"},
+		{location: "https://example.com/api/swagger.yaml", file: "models/pet.yaml", want: "https://example.com/api/models/pet.yaml"},
+		{location: "https://example.com/api/swagger.yaml", file: "https://example.org/pet.yaml", want: "https://example.org/pet.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.location+" "+tt.file, func(t *testing.T) {
+			if got := join(tt.location, filepath.FromSlash(tt.file)); filepath.ToSlash(got) != tt.want {
+				t.Errorf("join(%q, %q) = %q, want %q", tt.location, tt.file, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/heimbogdan/go-swagger-mock/common"
+	v2 "github.com/heimbogdan/go-swagger-mock/swagger_v2"
+	"os"
+)
+
+type validationReport struct {
+	Spec   string         `json:"spec"`
+	Valid  bool           `json:"valid"`
+	Issues []common.Issue `json:"issues"`
+}
+
+func validate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	spec := flags.String("spec", "", "swagger 2.0 spec file, directory, url or - for stdin (json or yaml)")
+	format := flags.String("format", "text", "output format: text or json")
+	_ = flags.Parse(args)
+	if len(*spec) == 0 {
+		flags.Usage()
+		return errors.New("missing --spec")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q, expected text or json", *format)
+	}
+
+	doc, err := v2.Load(*spec)
+	if err != nil {
+		return err
+	}
+	issues := common.Lint(doc)
+	report := validationReport{Spec: *spec, Valid: !common.HasErrors(issues), Issues: issues}
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printIssues(report)
+	}
+	if !report.Valid {
+		return fmt.Errorf("%s is not valid", *spec)
+	}
+	return nil
+}
+
+func printIssues(report validationReport) {
+	errs, warnings := 0, 0
+	for _, issue := range report.Issues {
+		fmt.Println(issue)
+		if issue.Severity == common.SeverityError {
+			errs++
+		} else {
+			warnings++
+		}
+	}
+	fmt.Printf("%s: %d errors, %d warnings\n", report.Spec, errs, warnings)
+}